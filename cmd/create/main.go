@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"github.com/cgalvisleon/jql/create"
 	"github.com/spf13/cobra"
 )
@@ -8,5 +10,8 @@ import (
 func main() {
 	var rootCmd = &cobra.Command{Use: "go"}
 	rootCmd.AddCommand(create.Create)
-	rootCmd.Execute()
+	err := rootCmd.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
 }
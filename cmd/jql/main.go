@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+
+	"github.com/cgalvisleon/jql/create"
+	"github.com/cgalvisleon/jql/migrate"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	var rootCmd = &cobra.Command{Use: "jql", SilenceUsage: true}
+	rootCmd.AddCommand(create.Create)
+	rootCmd.AddCommand(migrate.Migrate)
+	err := rootCmd.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
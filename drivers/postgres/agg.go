@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cgalvisleon/et/strs"
+	"github.com/cgalvisleon/jql/jdb"
+)
+
+/**
+* aggArg
+* @param from *jdb.From, name string
+* @return string
+**/
+func aggArg(from *jdb.From, name string) string {
+	if name == "" || name == "*" || strings.Contains(name, ".") || from == nil {
+		return name
+	}
+
+	return fmt.Sprintf("%s.%s", from.As, name)
+}
+
+/**
+* aggField
+* @param field *jdb.Field
+* @return string // the column or the attribute of an aggregate
+**/
+func aggField(field *jdb.Field) string {
+	if field.TypeColumn == jdb.ATTRIB {
+		return AttribAs(field)
+	}
+
+	return FieldAs(field)
+}
+
+/**
+* buildWindow
+* @param window *jdb.Window
+* @return string
+**/
+func buildWindow(window *jdb.Window) string {
+	partition := ""
+	for _, fld := range window.Partitions {
+		partition = strs.Append(partition, aggField(fld), ", ")
+	}
+
+	order := ""
+	for _, item := range window.Orders {
+		dir := "ASC"
+		if !item.Asc {
+			dir = "DESC"
+		}
+		order = strs.Append(order, fmt.Sprintf("%s %s", aggField(item.Field), dir), ", ")
+	}
+
+	result := ""
+	if partition != "" {
+		result = fmt.Sprintf("PARTITION BY %s", partition)
+	}
+	if order != "" {
+		result = strs.Append(result, fmt.Sprintf("ORDER BY %s", order), " ")
+	}
+
+	return fmt.Sprintf("OVER (%s)", result)
+}
+
+/**
+* AggAs
+* @param field *jdb.Field
+* @return string
+**/
+func AggAs(field *jdb.Field) string {
+	agg, ok := field.Field.(*jdb.Agg)
+	if !ok {
+		return FieldAs(field)
+	}
+
+	arg := aggArg(field.From, agg.Field)
+	if agg.Target != nil {
+		arg = aggField(agg.Target)
+		if agg.Target.TypeColumn == jdb.ATTRIB && slices.Contains([]string{"sum", "avg", "percentile_cont", "percentile_disc"}, agg.Agg) {
+			arg = fmt.Sprintf("(%s)::NUMERIC", arg)
+		}
+	}
+	result := ""
+	switch agg.Agg {
+	case "count":
+		if arg == "" || arg == "*" {
+			result = "COUNT(*)"
+		} else if agg.Distinct {
+			result = fmt.Sprintf("COUNT(DISTINCT %s)", arg)
+		} else {
+			result = fmt.Sprintf("COUNT(%s)", arg)
+		}
+	case "string_agg":
+		separator := ", "
+		if len(agg.Args) > 0 {
+			separator = fmt.Sprintf("%v", agg.Args[0])
+		}
+		if agg.Distinct {
+			arg = fmt.Sprintf("DISTINCT %s", arg)
+		}
+		result = fmt.Sprintf("STRING_AGG(%s::TEXT, %v)", arg, jdb.Quoted(separator))
+	case "percentile_cont", "percentile_disc":
+		fraction := interface{}(0.5)
+		if len(agg.Args) > 0 {
+			fraction = agg.Args[0]
+		}
+		result = fmt.Sprintf("%s(%v) WITHIN GROUP (ORDER BY %s)", strings.ToUpper(agg.Agg), jdb.Quoted(fraction), arg)
+	case "row_number", "rank", "dense_rank":
+		result = fmt.Sprintf("%s()", strings.ToUpper(agg.Agg))
+	case "lag", "lead":
+		offset := interface{}(1)
+		if len(agg.Args) > 0 {
+			offset = agg.Args[0]
+		}
+		result = fmt.Sprintf("%s(%s, %v)", strings.ToUpper(agg.Agg), arg, jdb.Quoted(offset))
+	default:
+		if agg.Distinct {
+			arg = fmt.Sprintf("DISTINCT %s", arg)
+		}
+		result = fmt.Sprintf("%s(%s)", strings.ToUpper(agg.Agg), arg)
+	}
+
+	if agg.Window != nil {
+		result = fmt.Sprintf("%s %s", result, buildWindow(agg.Window))
+	}
+
+	return result
+}
+
+/**
+* FieldExpr
+* @param field *jdb.Field
+* @return string
+**/
+func FieldExpr(field *jdb.Field) string {
+	switch field.TypeColumn {
+	case jdb.AGG:
+		return AggAs(field)
+	case jdb.ATTRIB:
+		return FieldKey(field)
+	default:
+		return FieldAs(field)
+	}
+}
+
+/**
+* fieldExpr
+* @param field *jdb.Field
+* @return (string, error) // FieldExpr, with subqueries built by the driver
+**/
+func (s *Driver) fieldExpr(field *jdb.Field) (string, error) {
+	if field.TypeColumn != jdb.SUBQUERY {
+		return FieldExpr(field), nil
+	}
+
+	ql, ok := field.Field.(*jdb.Ql)
+	if !ok {
+		return FieldExpr(field), nil
+	}
+
+	return s.buildSubquery(ql)
+}
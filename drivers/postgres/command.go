@@ -43,7 +43,7 @@ func (s *Driver) buildInsert(cmd *jdb.Cmd) (string, error) {
 	for k, v := range data {
 		col := from.FindColumn(k)
 		if col != nil && col.TypeColumn == jdb.COLUMN {
-			val := fmt.Sprintf(`%v`, jdb.Quoted(v))
+			val := fmt.Sprintf(`%v`, s.quotedValue(from, col, v))
 			into = strs.Append(into, k, ", ")
 			values = strs.Append(values, val, ", ")
 			continue
@@ -81,7 +81,7 @@ func (s *Driver) buildUpdate(cmd *jdb.Cmd) (string, error) {
 	for k, v := range data {
 		col := from.FindColumn(k)
 		if col != nil && col.TypeColumn == jdb.COLUMN {
-			val := fmt.Sprintf(`%v`, jdb.Quoted(v))
+			val := fmt.Sprintf(`%v`, s.quotedValue(from, col, v))
 			sets = strs.Append(sets, fmt.Sprintf(`%s = %s`, k, val), ",\n")
 			continue
 		}
@@ -113,6 +113,17 @@ func (s *Driver) buildUpdate(cmd *jdb.Cmd) (string, error) {
 		where = def
 	}
 
+	scope, err := s.buildWhere(cmd.Scope())
+	if err != nil {
+		return "", err
+	}
+
+	if where != "" && scope != "" {
+		where = fmt.Sprintf("(%s)\nAND %s", where, scope)
+	} else if scope != "" {
+		where = scope
+	}
+
 	sql := fmt.Sprintf("UPDATE %s SET\n%s", table, sets)
 	sql = strs.Append(sql, where, "\nWHERE ")
 	sql = fmt.Sprintf("%s\nRETURNING %s;", sql, returning)
@@ -139,12 +150,23 @@ func (s *Driver) buildDelete(cmd *jdb.Cmd) (string, error) {
 		where = def
 	}
 
+	scope, err := s.buildWhere(cmd.Scope())
+	if err != nil {
+		return "", err
+	}
+
+	if where != "" && scope != "" {
+		where = fmt.Sprintf("(%s)\nAND %s", where, scope)
+	} else if scope != "" {
+		where = scope
+	}
+
 	if useAtribs {
 		returning = fmt.Sprintf("to_jsonb(%s.*) - '%s' AS result", table, from.SourceField)
 	}
 
 	sql := fmt.Sprintf(`DELETE FROM %s`, table)
 	sql = strs.Append(sql, where, "\nWHERE ")
-	sql = fmt.Sprintf(`%s\nRETURNING %s;`, sql, returning)
+	sql = fmt.Sprintf("%s\nRETURNING %s;", sql, returning)
 	return sql, nil
 }
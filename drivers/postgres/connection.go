@@ -3,10 +3,42 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/cgalvisleon/et/et"
 )
 
+/**
+* options
+* @params et.Json
+* @return string // ssl, timeouts in seconds and search_path of the connection
+**/
+func options(params et.Json) string {
+	result := url.Values{}
+	sslmode := params.Str("sslmode")
+	if sslmode == "" {
+		sslmode = "disable"
+	}
+	result.Set("sslmode", sslmode)
+	result.Set("application_name", params.Str("app"))
+	for _, key := range []string{"sslrootcert", "sslcert", "sslkey", "search_path"} {
+		value := params.Str(key)
+		if value != "" {
+			result.Set(key, value)
+		}
+	}
+	for key, unit := range map[string]string{"connect_timeout": "", "statement_timeout": "s"} {
+		value := params.Int(key)
+		if value > 0 {
+			result.Set(key, strconv.Itoa(value)+unit)
+		}
+	}
+
+	return result.Encode()
+}
+
 /**
 * defaultChain
 * @params et.Json
@@ -17,8 +49,7 @@ func defaultChain(params et.Json) (string, error) {
 	password := params.Str("password")
 	host := params.Str("host")
 	port := params.Int("port")
-	app := params.Str("app")
-	return fmt.Sprintf(`%s://%s:%s@%s:%d/%s?sslmode=disable&application_name=%s`, driver, username, password, host, port, "postgres", app), nil
+	return fmt.Sprintf(`%s://%s:%s@%s:%d/%s?%s`, driver, username, password, host, port, "postgres", options(params)), nil
 }
 
 /**
@@ -52,7 +83,7 @@ func chain(params et.Json) (string, error) {
 		return "", fmt.Errorf("app is required")
 	}
 
-	result := fmt.Sprintf(`%s://%s:%s@%s:%d/%s?sslmode=disable&application_name=%s`, driver, username, password, host, port, database, app)
+	result := fmt.Sprintf(`%s://%s:%s@%s:%d/%s?%s`, driver, username, password, host, port, database, options(params))
 	return result, nil
 }
 
@@ -68,8 +99,28 @@ func connectTo(chain string) (*sql.DB, error) {
 	}
 
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, err
 	}
 
 	return db, nil
 }
+
+/**
+* setPool
+* @param db *sql.DB, params et.Json // max_open_conns, max_idle_conns, conn_max_lifetime and conn_max_idle_time in seconds
+**/
+func setPool(db *sql.DB, params et.Json) {
+	if value := params.Int("max_open_conns"); value > 0 {
+		db.SetMaxOpenConns(value)
+	}
+	if value := params.Int("max_idle_conns"); value > 0 {
+		db.SetMaxIdleConns(value)
+	}
+	if value := params.Int("conn_max_lifetime"); value > 0 {
+		db.SetConnMaxLifetime(time.Duration(value) * time.Second)
+	}
+	if value := params.Int("conn_max_idle_time"); value > 0 {
+		db.SetConnMaxIdleTime(time.Duration(value) * time.Second)
+	}
+}
@@ -77,3 +77,39 @@ func DropDatabase(db *sql.DB, name string) error {
 
 	return nil
 }
+
+/**
+* TerminateSessions
+* @param db *sql.DB, name string
+* @return error
+**/
+func TerminateSessions(db *sql.DB, name string) error {
+	sql := `
+	SELECT pg_terminate_backend(pid)
+	FROM pg_stat_activity
+	WHERE UPPER(datname) = UPPER($1)
+	AND pid <> pg_backend_pid();`
+	rows, err := db.Query(sql, name)
+	if err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+/**
+* RenameDatabase
+* @param db *sql.DB, name, newName string
+* @return error
+**/
+func RenameDatabase(db *sql.DB, name, newName string) error {
+	sql := fmt.Sprintf(`ALTER DATABASE %s RENAME TO %s;`, name, newName)
+	_, err := db.Exec(sql)
+	if err != nil {
+		return err
+	}
+
+	logs.Logf(driver, `Database %s renamed to %s`, name, newName)
+
+	return nil
+}
@@ -3,8 +3,12 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
+	"strings"
+	"sync"
 
+	"github.com/cgalvisleon/et/et"
 	"github.com/cgalvisleon/et/logs"
+	"github.com/cgalvisleon/et/strs"
 	"github.com/cgalvisleon/jql/jdb"
 )
 
@@ -14,7 +18,12 @@ func init() {
 	jdb.Register(driver, new)
 }
 
-type Driver struct{}
+type Driver struct {
+	postgis    bool
+	trgm       bool
+	geometries map[string]bool
+	mu         sync.RWMutex
+}
 
 /**
 * newDriver
@@ -45,6 +54,7 @@ func (s *Driver) Connect(db *jdb.DB) (*sql.DB, error) {
 
 	err = CreateDatabase(result, db.Name)
 	if err != nil {
+		result.Close()
 		return nil, err
 	}
 
@@ -64,6 +74,19 @@ func (s *Driver) Connect(db *jdb.DB) (*sql.DB, error) {
 	if err != nil {
 		return nil, err
 	}
+	setPool(result, params)
+
+	s.postgis, err = LoadExtension(result, "postgis")
+	if err != nil {
+		result.Close()
+		return nil, err
+	}
+
+	s.trgm, err = LoadExtension(result, "pg_trgm")
+	if err != nil {
+		result.Close()
+		return nil, err
+	}
 
 	host := params.Str("host")
 	port := params.Int("port")
@@ -72,6 +95,30 @@ func (s *Driver) Connect(db *jdb.DB) (*sql.DB, error) {
 	return result, nil
 }
 
+/**
+* Replica
+* @param db *jdb.DB, params et.Json // the replica of db, read only
+* @return *sql.DB, error
+**/
+func (s *Driver) Replica(db *jdb.DB, params et.Json) (*sql.DB, error) {
+	chain, err := chain(params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := connectTo(chain)
+	if err != nil {
+		return nil, err
+	}
+	setPool(result, params)
+
+	host := params.Str("host")
+	port := params.Int("port")
+	logs.Logf(driver, `Connected to replica %s:%s:%d`, host, db.Name, port)
+
+	return result, nil
+}
+
 /**
 * Load
 * @param model *Model
@@ -98,7 +145,25 @@ func (s *Driver) Load(model *jdb.Model) (string, error) {
 * @return (string, error)
 **/
 func (s *Driver) Mutate(model *jdb.Model) (string, error) {
-	return "", nil
+	result := ""
+	for _, backfill := range model.Backfills() {
+		value, err := s.buildSubquery(backfill.Query)
+		if err != nil {
+			return "", err
+		}
+
+		if backfill.Zero != nil {
+			value = fmt.Sprintf("COALESCE(%s, %v)", value, jdb.Quoted(backfill.Zero))
+		}
+		def := fmt.Sprintf("UPDATE %s SET %s = %s;", model.Table, backfill.Column, value)
+		result = strs.Append(result, def, "\n")
+	}
+
+	if result != "" {
+		logs.Logf(driver, MSG_MUTATE_MODEL, model.Name, model.Version)
+	}
+
+	return result, nil
 }
 
 /**
@@ -136,3 +201,98 @@ func (s *Driver) Command(cmd *jdb.Cmd) (string, error) {
 
 	return result, nil
 }
+
+/**
+* Lock
+* @param key int64
+* @return (string, error)
+**/
+func (s *Driver) Lock(key int64) (string, error) {
+	return fmt.Sprintf("SELECT pg_advisory_xact_lock(%d);", key), nil
+}
+
+/**
+* Cursor
+* @param name, query string, rows int
+* @return (string, string, string) // declare, fetch, close
+**/
+func (s *Driver) Cursor(name, query string, rows int) (string, string, string) {
+	query = strings.TrimSuffix(strings.TrimSpace(query), ";")
+	declare := fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR\n%s;", name, query)
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s;", rows, name)
+	close := fmt.Sprintf("CLOSE %s;", name)
+	return declare, fetch, close
+}
+
+/**
+* admin
+* @param db *jdb.DB
+* @return (*sql.DB, error) // connection to the default database, to manage db
+**/
+func (s *Driver) admin(db *jdb.DB) (*sql.DB, error) {
+	defaultChain, err := defaultChain(db.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := connectTo(defaultChain)
+	if err != nil {
+		return nil, err
+	}
+
+	err = TerminateSessions(result, db.Name)
+	if err != nil {
+		result.Close()
+		return nil, err
+	}
+
+	return result, nil
+}
+
+/**
+* Drop
+* @param db *jdb.DB
+* @return error
+**/
+func (s *Driver) Drop(db *jdb.DB) error {
+	admin, err := s.admin(db)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	return DropDatabase(admin, db.Name)
+}
+
+/**
+* Rename
+* @param db *jdb.DB, name string
+* @return error
+**/
+func (s *Driver) Rename(db *jdb.DB, name string) error {
+	admin, err := s.admin(db)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	return RenameDatabase(admin, db.Name, name)
+}
+
+/**
+* DropSchema
+* @param name string
+* @return (string, error)
+**/
+func (s *Driver) DropSchema(name string) (string, error) {
+	return fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", name), nil
+}
+
+/**
+* RenameSchema
+* @param name, newName string
+* @return (string, error)
+**/
+func (s *Driver) RenameSchema(name, newName string) (string, error) {
+	return fmt.Sprintf("ALTER SCHEMA %s RENAME TO %s;", name, newName), nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/cgalvisleon/et/strs"
+	"github.com/cgalvisleon/jql/jdb"
+	"github.com/lib/pq"
+)
+
+var sqlStates = map[pq.ErrorCode]error{
+	"23505": jdb.ErrDuplicate,     // unique_violation
+	"23503": jdb.ErrForeignKey,    // foreign_key_violation
+	"23502": jdb.ErrNotNull,       // not_null_violation
+	"23514": jdb.ErrCheck,         // check_violation
+	"40001": jdb.ErrSerialization, // serialization_failure
+	"40P01": jdb.ErrDeadlock,      // deadlock_detected
+}
+
+/**
+* MapError
+* @param err error
+* @return error
+**/
+func (s *Driver) MapError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	kind, ok := sqlStates[pqErr.Code]
+	if !ok {
+		return err
+	}
+
+	result := jdb.NewDbError(kind, string(pqErr.Code), err)
+	result.Model = strs.Append(pqErr.Schema, pqErr.Table, ".")
+	result.Constraint = pqErr.Constraint
+	result.Field = pqErr.Column
+	if result.Field == "" {
+		result.Field = detailField(pqErr.Detail)
+	}
+
+	return result
+}
+
+/**
+* detailField
+* @param detail string // Key (email)=(x) already exists.
+* @return string
+**/
+func detailField(detail string) string {
+	pattern := regexp.MustCompile(`^Key \(([^)]+)\)=`)
+	matches := pattern.FindStringSubmatch(detail)
+	if len(matches) != 2 {
+		return ""
+	}
+
+	return matches[1]
+}
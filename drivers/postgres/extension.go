@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cgalvisleon/et/logs"
+	"github.com/cgalvisleon/jql/jdb"
+)
+
+/**
+* LoadExtension
+* @param db *sql.DB, name string
+* @return bool, error
+**/
+func LoadExtension(db *sql.DB, name string) (bool, error) {
+	rows, err := db.Query(`
+	SELECT EXISTS(
+		SELECT 1
+		FROM pg_available_extensions
+		WHERE name = $1);`, name)
+	if err != nil {
+		return false, err
+	}
+
+	items := jdb.RowsToItems(rows)
+	if items.Count == 0 || !items.Bool(0, "exists") {
+		return false, nil
+	}
+
+	_, err = db.Exec(fmt.Sprintf(`CREATE EXTENSION IF NOT EXISTS %s;`, name))
+	if err != nil {
+		logs.Logf(driver, MSG_EXTENSION_UNAVAILABLE, name, err)
+		return false, nil
+	}
+
+	return true, nil
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cgalvisleon/jql/jdb"
+)
+
+/**
+* srid
+* @param column *jdb.Column
+* @return int
+**/
+func srid(column *jdb.Column) int {
+	if column == nil || column.Srid == 0 {
+		return jdb.SRID
+	}
+
+	return column.Srid
+}
+
+/**
+* geometry
+* @param val any, srid int // GeoJSON
+* @return string
+**/
+func geometry(val any, srid int) string {
+	return fmt.Sprintf("ST_SetSRID(ST_GeomFromGeoJSON(%v), %d)", jdb.Quoted(val), srid)
+}
+
+/**
+* geoKey
+* @param schema, name, column string
+* @return string
+**/
+func geoKey(schema, name, column string) string {
+	return strings.ToLower(fmt.Sprintf("%s.%s.%s", schema, name, column))
+}
+
+/**
+* loadGeometries
+* @param model *jdb.Model, exists bool
+* @return error // the storage of each geometry column is read from the catalog, a new column is geometry with PostGIS and JSONB without it
+**/
+func (s *Driver) loadGeometries(model *jdb.Model, exists bool) error {
+	types := map[string]string{}
+	if exists {
+		var err error
+		types, err = ColumnTypes(model.Db(), model.Schema, model.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.geometries == nil {
+		s.geometries = make(map[string]bool)
+	}
+	for _, column := range model.Columns {
+		if column.TypeColumn != jdb.COLUMN || column.TypeData != jdb.GEOMETRY {
+			continue
+		}
+
+		stored := s.postgis
+		if udt, ok := types[column.Name]; ok {
+			stored = udt == "geometry"
+		}
+		s.geometries[geoKey(model.Schema, model.Name, column.Name)] = stored
+	}
+
+	return nil
+}
+
+/**
+* isGeometry
+* @param schema, name string, column *jdb.Column
+* @return bool // true when the column is stored as a PostGIS geometry
+**/
+func (s *Driver) isGeometry(schema, name string, column *jdb.Column) bool {
+	if column == nil || column.TypeData != jdb.GEOMETRY {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.geometries[geoKey(schema, name, column.Name)]
+}
+
+/**
+* fieldGeometry
+* @param field *jdb.Field
+* @return bool
+**/
+func (s *Driver) fieldGeometry(field *jdb.Field) bool {
+	if field == nil || field.From == nil {
+		return false
+	}
+
+	return s.isGeometry(field.From.Schema, field.From.Name, field.Column())
+}
+
+/**
+* geometryKey
+* @param field *jdb.Field
+* @return string, error // a JSONB column is converted, which needs PostGIS
+**/
+func (s *Driver) geometryKey(field *jdb.Field) (string, error) {
+	key := FieldAs(field)
+	if s.fieldGeometry(field) {
+		return key, nil
+	}
+
+	if !s.postgis {
+		return "", fmt.Errorf(MSG_POSTGIS_REQUIRED, field.As)
+	}
+
+	return fmt.Sprintf("ST_SetSRID(ST_GeomFromGeoJSON(%s::text), %d)", key, srid(field.Column())), nil
+}
+
+/**
+* buildGeoCondition
+* @param cond *jdb.Condition
+* @return string, error
+**/
+func (s *Driver) buildGeoCondition(cond *jdb.Condition) (string, error) {
+	value, ok := cond.Value.(jdb.GeoValue)
+	if !ok {
+		return "", fmt.Errorf(MSG_GEO_VALUE_REQUIRED, cond.Field.As)
+	}
+
+	key, err := s.geometryKey(cond.Field)
+	if err != nil {
+		return "", err
+	}
+
+	geom := geometry(value.Geometry, srid(cond.Field.Column()))
+	switch cond.Operator {
+	case jdb.OpWithin:
+		return fmt.Sprintf("ST_Within(%s, %s)", key, geom), nil
+	case jdb.OpIntersects:
+		return fmt.Sprintf("ST_Intersects(%s, %s)", key, geom), nil
+	case jdb.OpDWithin:
+		return fmt.Sprintf("ST_DWithin(%s::geography, %s::geography, %v)", key, geom, value.Distance), nil
+	}
+
+	return "", fmt.Errorf(MSG_GEO_OPERATOR_INVALID, cond.Operator)
+}
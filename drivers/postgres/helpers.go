@@ -1,7 +1,11 @@
 package postgres
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/cgalvisleon/et/strs"
+	"github.com/cgalvisleon/et/utility"
 	"github.com/cgalvisleon/jql/jdb"
 )
 
@@ -10,7 +14,175 @@ func FieldAs(field *jdb.Field) string {
 		return field.As
 	}
 
+	name, ok := field.Field.(string)
+	if !ok || name == "" {
+		name = field.As
+	}
+
 	result := field.From.As
-	result = strs.Append(result, field.As, ".")
+	result = strs.Append(result, name, ".")
 	return result
 }
+
+/**
+* fromAlias
+* @param from *jdb.From
+* @return string
+**/
+func fromAlias(from *jdb.From) string {
+	if from.As != "" {
+		return from.As
+	}
+
+	return from.Table
+}
+
+/**
+* fromJson
+* @param from *jdb.From, hiddens []string
+* @return string // the row as jsonb, attributes of the source merged and hidden fields removed
+**/
+func fromJson(from *jdb.From, hiddens []string) string {
+	as := fromAlias(from)
+	hiddens = append(append([]string{}, from.Hidden()...), hiddens...)
+	model := from.Model()
+	if model == nil || model.SourceField == "" {
+		if len(hiddens) == 0 {
+			return fmt.Sprintf("to_jsonb(%s)", as)
+		}
+		return fmt.Sprintf("to_jsonb(%s) - ARRAY[%s]", as, strs.JoinQuoted(hiddens, ", "))
+	}
+
+	hiddens = utility.Add(hiddens, model.SourceField)
+	return fmt.Sprintf("%s.%s||(to_jsonb(%s) - ARRAY[%s])", as, model.SourceField, as, strs.JoinQuoted(hiddens, ", "))
+}
+
+/**
+* AttribAs
+* @param field *jdb.Field
+* @return string
+**/
+func AttribAs(field *jdb.Field) string {
+	name, ok := field.Field.(string)
+	if !ok {
+		return FieldAs(field)
+	}
+
+	source := jdb.SOURCE
+	if field.From != nil {
+		source = fmt.Sprintf("%s.%s", fromAlias(field.From), source)
+	}
+
+	return fmt.Sprintf("%s->'%s'", source, name)
+}
+
+/**
+* getType
+* @param column *jdb.Column
+* @return string
+**/
+func getType(column *jdb.Column) string {
+	types := map[jdb.TypeData]string{
+		jdb.ANY:      "VARCHAR(250)",
+		jdb.BYTES:    "BYTEA",
+		jdb.INT:      "BIGINT",
+		jdb.FLOAT:    "DOUBLE PRECISION",
+		jdb.KEY:      "VARCHAR(80)",
+		jdb.TEXT:     "VARCHAR(250)",
+		jdb.MEMO:     "TEXT",
+		jdb.JSON:     "JSONB",
+		jdb.DATETIME: "TIMESTAMP",
+		jdb.BOOLEAN:  "BOOLEAN",
+		jdb.GEOMETRY: "JSONB",
+		jdb.DECIMAL:  "NUMERIC",
+		jdb.UUID:     "UUID",
+		jdb.DATE:     "DATE",
+		jdb.TIME:     "TIME",
+		jdb.INTERVAL: "INTERVAL",
+		jdb.ENUM:     "VARCHAR(80)",
+	}
+
+	switch column.TypeData {
+	case jdb.KEY, jdb.TEXT, jdb.ENUM:
+		if column.Length > 0 {
+			return fmt.Sprintf("VARCHAR(%d)", column.Length)
+		}
+	case jdb.DECIMAL:
+		if column.Precision > 0 {
+			return fmt.Sprintf("NUMERIC(%d, %d)", column.Precision, column.Scale)
+		}
+	case jdb.ARRAY:
+		elem := column.Elem
+		if elem == "" || elem == jdb.ARRAY {
+			elem = jdb.TEXT
+		}
+		return fmt.Sprintf("%s[]", getType(&jdb.Column{TypeData: elem, Length: column.Length, Precision: column.Precision, Scale: column.Scale}))
+	}
+
+	if t, ok := types[column.TypeData]; ok {
+		return t
+	}
+
+	return "VARCHAR(250)"
+}
+
+/**
+* defaultValue
+* @param tp jdb.TypeData
+* @return string
+**/
+func defaultValue(tp jdb.TypeData) string {
+	values := map[jdb.TypeData]string{
+		jdb.ANY:      "",
+		jdb.BYTES:    "''",
+		jdb.INT:      "0",
+		jdb.FLOAT:    "0.0",
+		jdb.KEY:      "''",
+		jdb.TEXT:     "''",
+		jdb.MEMO:     "''",
+		jdb.JSON:     "'{}'",
+		jdb.DATETIME: "NOW()",
+		jdb.BOOLEAN:  "FALSE",
+		jdb.GEOMETRY: "'{}'",
+		jdb.DECIMAL:  "0",
+		jdb.UUID:     "NULL",
+		jdb.DATE:     "CURRENT_DATE",
+		jdb.TIME:     "LOCALTIME",
+		jdb.INTERVAL: "'0 seconds'",
+		jdb.ARRAY:    "'{}'",
+		jdb.ENUM:     "NULL",
+	}
+
+	if t, ok := values[tp]; ok {
+		return t
+	}
+
+	return ""
+}
+
+/**
+* quotedValue
+* @param model *jdb.Model, column *jdb.Column, val any
+* @return any
+**/
+func (s *Driver) quotedValue(model *jdb.Model, column *jdb.Column, val any) any {
+	if val != nil && s.isGeometry(model.Schema, model.Name, column) {
+		return geometry(val, srid(column))
+	}
+
+	if column == nil || column.TypeData != jdb.ARRAY {
+		return jdb.Quoted(val)
+	}
+
+	items := reflect.ValueOf(val)
+	if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+		return jdb.Quoted(val)
+	}
+
+	result := ""
+	for i := 0; i < items.Len(); i++ {
+		result = strs.Append(result, fmt.Sprintf("%v", jdb.Quoted(items.Index(i).Interface())), ", ")
+	}
+
+	return fmt.Sprintf("ARRAY[%s]::%s", result, getType(column))
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cgalvisleon/jql/jdb"
+)
+
+func keysetModel(t *testing.T) *jdb.Model {
+	t.Helper()
+	db := &jdb.DB{Name: "test", Schemas: map[string]*jdb.Schema{}}
+	model, err := db.NewModel("public", "users", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	model.DefineColumn("id", jdb.INT, 0)
+	model.DefineColumn("name", jdb.TEXT, "")
+	model.DefinePrimaryKeys("id")
+	model.Table = "public.users"
+	return model
+}
+
+func TestBuildKeyset(t *testing.T) {
+	model := keysetModel(t)
+	tests := []struct {
+		name   string
+		values []interface{}
+		before bool
+		want   string
+		err    error
+	}{
+		{
+			name:   "after",
+			values: []interface{}{"ana", json.Number("10")},
+			want:   "((A.name > 'ana')\nOR (A.name = 'ana' AND A.id > 10))",
+		},
+		{
+			name:   "before",
+			values: []interface{}{"ana", json.Number("10")},
+			before: true,
+			want:   "((A.name < 'ana')\nOR (A.name = 'ana' AND A.id < 10))",
+		},
+		{
+			name:   "escaped",
+			values: []interface{}{"x' OR 1=1 --", json.Number("10")},
+			want:   "((A.name > 'x'' OR 1=1 --')\nOR (A.name = 'x'' OR 1=1 --' AND A.id > 10))",
+		},
+		{
+			name:   "wrong type",
+			values: []interface{}{"ana", "1; DROP TABLE users"},
+			err:    jdb.ErrInvalidCursor,
+		},
+		{
+			name:   "object",
+			values: []interface{}{map[string]interface{}{"a": 1}, json.Number("10")},
+			err:    jdb.ErrInvalidCursor,
+		},
+		{
+			name:   "missing values",
+			values: []interface{}{"ana"},
+			err:    jdb.ErrInvalidCursor,
+		},
+	}
+
+	driver := &Driver{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ql := jdb.NewQuery(model, "A").OrderBy("name")
+			ql.Cursor = &jdb.Cursor{Values: tt.values, Before: tt.before}
+			got, err := driver.buildKeyset(ql)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("buildKeyset() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildKeyset(): %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("buildKeyset() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"strings"
 
 	"github.com/cgalvisleon/et/logs"
 	"github.com/cgalvisleon/et/strs"
@@ -24,8 +26,13 @@ func (s *Driver) buildModel(model *jdb.Model) (string, error) {
 		return "", err
 	}
 
+	err = s.loadGeometries(model, exists)
+	if err != nil {
+		return "", err
+	}
+
 	if exists {
-		return "", nil
+		return s.buildAlter(model)
 	}
 
 	sql, err := s.buildSchema(model)
@@ -56,7 +63,11 @@ func (s *Driver) buildModel(model *jdb.Model) (string, error) {
 		return "", err
 	}
 
-	def, err = s.buildForeignKeys(model)
+	if def != "" {
+		sql = strs.Append(sql, def, "\n")
+	}
+
+	def, err = s.buildMigrations(model)
 	if err != nil {
 		return "", err
 	}
@@ -65,6 +76,11 @@ func (s *Driver) buildModel(model *jdb.Model) (string, error) {
 		sql = strs.Append(sql, def, "\n")
 	}
 
+	def, err = s.buildForeignKeys(model)
+	if err != nil {
+		return "", err
+	}
+
 	if def != "" {
 		sql = strs.Append(sql, def, "\n")
 	}
@@ -100,83 +116,105 @@ func (s *Driver) buildSchema(model *jdb.Model) (string, error) {
 * @return (string, error)
 **/
 func (s *Driver) buildTable(model *jdb.Model) (string, error) {
-	getType := func(tp jdb.TypeData) string {
-		types := map[jdb.TypeData]string{
-			jdb.ANY:      "VARCHAR(250)",
-			jdb.BYTES:    "BYTEA",
-			jdb.INT:      "BIGINT",
-			jdb.FLOAT:    "DOUBLE PRECISION",
-			jdb.KEY:      "VARCHAR(80)",
-			jdb.TEXT:     "VARCHAR(250)",
-			jdb.MEMO:     "TEXT",
-			jdb.JSON:     "JSONB",
-			jdb.DATETIME: "TIMESTAMP",
-			jdb.BOOLEAN:  "BOOLEAN",
-			jdb.GEOMETRY: "JSONB",
+	columnsDef := ""
+	for _, column := range model.Columns {
+		if column.TypeColumn != jdb.COLUMN {
+			continue
 		}
 
-		if t, ok := types[tp]; ok {
-			return t
-		}
+		def := s.buildColumn(model, column)
+		columnsDef = strs.Append(columnsDef, def, ",")
+	}
 
-		return "VARCHAR(250)"
-	}
-
-	defaultValue := func(tp jdb.TypeData) string {
-		values := map[jdb.TypeData]string{
-			jdb.ANY:      "",
-			jdb.BYTES:    "''",
-			jdb.INT:      "0",
-			jdb.FLOAT:    "0.0",
-			jdb.KEY:      "''",
-			jdb.TEXT:     "''",
-			jdb.MEMO:     "''",
-			jdb.JSON:     "'{}'",
-			jdb.DATETIME: "NOW()",
-			jdb.BOOLEAN:  "FALSE",
-			jdb.GEOMETRY: "'{}'",
-		}
+	def, err := s.buildPrimaryKeys(model)
+	if err != nil {
+		return "", err
+	}
 
-		if t, ok := values[tp]; ok {
-			return t
-		}
+	if def != "" {
+		columnsDef = strs.Append(columnsDef, def, ",\n\t")
+	}
+
+	table := model.Table
+	result := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", table, columnsDef)
 
-		return ""
+	return result, nil
+}
+
+/**
+* buildColumn
+* @param model *jdb.Model, column *jdb.Column
+* @return string
+**/
+func (s *Driver) buildColumn(model *jdb.Model, column *jdb.Column) string {
+	tpData := column.TypeData
+	if tpData == jdb.TSVECTOR {
+		return s.buildSearchColumn(model, column)
 	}
 
-	columns := model.Columns
-	columnsDef := ""
-	for _, column := range columns {
-		tpColumn := column.TypeColumn
-		if tpColumn != jdb.COLUMN {
-			continue
-		}
-		tpData := column.TypeData
-		tp := getType(tpData)
-		df := column.Default
-		switch v := df.(type) {
-		case string:
-			if v == "" {
-				df = defaultValue(tpData)
-			}
-		default:
+	tp := getType(column)
+	geometry := s.isGeometry(model.Schema, model.Name, column)
+	if geometry {
+		tp = fmt.Sprintf("geometry(Geometry, %d)", srid(column))
+	}
+	df := column.Default
+	switch v := df.(type) {
+	case string:
+		if v == "" {
 			df = defaultValue(tpData)
 		}
-		def := fmt.Sprintf("\n\t%s %s DEFAULT %v", column.Name, tp, df)
-		columnsDef = strs.Append(columnsDef, def, ",")
+	default:
+		df = defaultValue(tpData)
+	}
+	if geometry {
+		df = "NULL"
 	}
+	return fmt.Sprintf("\n\t%s %s DEFAULT %v", column.Name, tp, df)
+}
 
-	def, err := s.buildPrimaryKeys(model)
+/**
+* buildAlter
+* @param model *jdb.Model
+* @return (string, error) // the columns and indexes defined after the table was created
+**/
+func (s *Driver) buildAlter(model *jdb.Model) (string, error) {
+	result := ""
+	for _, column := range model.Columns {
+		if column.TypeColumn != jdb.COLUMN {
+			continue
+		}
+
+		def := s.buildColumn(model, column)
+		def = fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;", model.Table, strings.TrimSpace(def))
+		result = strs.Append(result, def, "\n")
+	}
+
+	def, err := s.buildIndexes(model)
 	if err != nil {
 		return "", err
 	}
 
 	if def != "" {
-		columnsDef = strs.Append(columnsDef, def, ",\n\t")
+		result = strs.Append(result, def, "\n")
 	}
 
-	table := model.Table
-	result := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", table, columnsDef)
+	def, err = s.buildUniqueIndex(model)
+	if err != nil {
+		return "", err
+	}
+
+	if def != "" {
+		result = strs.Append(result, def, "\n")
+	}
+
+	def, err = s.buildMigrations(model)
+	if err != nil {
+		return "", err
+	}
+
+	if def != "" {
+		result = strs.Append(result, def, "\n")
+	}
 
 	return result, nil
 }
@@ -211,7 +249,7 @@ func (s *Driver) buildForeignKeys(model *jdb.Model) (string, error) {
 	result := ""
 	for _, foreignKey := range model.ForeignKeys {
 		name := fmt.Sprintf("fk_%s_%s", model.Name, foreignKey.To.Name)
-		to := foreignKey.To.Table
+		to := fmt.Sprintf("%s.%s", foreignKey.To.Schema, foreignKey.To.Name)
 		fks := ""
 		ks := ""
 		for k, fk := range foreignKey.Keys {
@@ -240,23 +278,40 @@ func (s *Driver) buildForeignKeys(model *jdb.Model) (string, error) {
 * @return (string, error)
 **/
 func (s *Driver) buildIndexes(model *jdb.Model) (string, error) {
-	if len(model.Indexes) == 0 {
+	indexes := model.Indexes
+	for _, column := range model.Columns {
+		if column.TypeColumn == jdb.COLUMN && column.TypeData == jdb.GEOMETRY {
+			indexes = utility.Add(indexes, column.Name)
+		}
+	}
+
+	if len(indexes) == 0 {
 		return "", nil
 	}
 
 	table := model.Table
 	name := model.Name
 	result := ""
-	for _, v := range model.Indexes {
+	for _, v := range indexes {
 		def := fmt.Sprintf("idx_%s_%s", name, v)
+		column := model.FindColumn(v)
 		if v == jdb.SOURCE {
 			def = fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (%s);", def, table, v)
+		} else if s.isGeometry(model.Schema, model.Name, column) {
+			def = fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIST (%s);", def, table, v)
+		} else if column != nil && (column.TypeData == jdb.GEOMETRY || column.TypeData == jdb.TSVECTOR) {
+			def = fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (%s);", def, table, v)
 		} else {
 			def = fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s(%s);", def, table, v)
 		}
 		result = strs.Append(result, def, "\n")
 	}
 
+	def := s.buildSearchIndexes(model)
+	if def != "" {
+		result = strs.Append(result, def, "\n")
+	}
+
 	return result, nil
 }
 
@@ -281,3 +336,153 @@ func (s *Driver) buildUniqueIndex(model *jdb.Model) (string, error) {
 
 	return result, nil
 }
+
+/**
+* buildIndexSpecs
+* @param model *jdb.Model
+* @return (string, error) // each index keeps the signature of its spec in a comment, a changed spec is dropped and created again
+**/
+func (s *Driver) buildIndexSpecs(model *jdb.Model) (string, error) {
+	if len(model.IndexSpecs) == 0 {
+		return "", nil
+	}
+
+	comments, err := IndexComments(model.Db(), model.Schema, model.Name)
+	if err != nil {
+		return "", err
+	}
+
+	table := model.Table
+	result := ""
+	for _, spec := range model.IndexSpecs {
+		columns := ""
+		for _, column := range spec.Columns {
+			if !spec.IsColumn(column) {
+				column = fmt.Sprintf("(%s)", column)
+			}
+			columns = strs.Append(columns, column, ", ")
+		}
+
+		index := "INDEX"
+		if spec.Unique {
+			index = "UNIQUE INDEX"
+		}
+
+		name := fmt.Sprintf("ix_%s_%s", model.Name, spec.Name)
+		def := fmt.Sprintf("CREATE %s %s ON %s", index, name, table)
+		if spec.Method != "" && spec.Method != jdb.BTREE {
+			def = fmt.Sprintf("%s USING %s", def, strings.ToUpper(string(spec.Method)))
+		}
+		def = fmt.Sprintf("%s(%s)", def, columns)
+		if spec.Where != "" {
+			def = fmt.Sprintf("%s WHERE %s", def, spec.Where)
+		}
+
+		sign := signature(def)
+		comment, exists := comments[name]
+		if exists && comment == sign {
+			continue
+		}
+
+		if exists {
+			result = strs.Append(result, fmt.Sprintf("DROP INDEX IF EXISTS %s.%s;", model.Schema, name), "\n")
+		}
+		result = strs.Append(result, def+";", "\n")
+		result = strs.Append(result, fmt.Sprintf("COMMENT ON INDEX %s.%s IS '%s';", model.Schema, name, sign), "\n")
+	}
+
+	return result, nil
+}
+
+/**
+* signature
+* @param def string
+* @return string // kept in the comment of an index or constraint to detect a changed definition
+**/
+func signature(def string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(def)))[:16]
+}
+
+/**
+* buildChecks
+* @param model *jdb.Model
+* @return (string, error) // each constraint keeps the signature of its check in a comment, a changed check is dropped and added again
+**/
+func (s *Driver) buildChecks(model *jdb.Model) (string, error) {
+	checks := map[string]string{}
+	names := []string{}
+	for _, check := range model.Checks {
+		if !check.IsColumn() {
+			continue
+		}
+
+		cond, err := s.buildWhere(check.Conditions)
+		if err != nil {
+			return "", err
+		}
+
+		name := fmt.Sprintf("chk_%s_%s", model.Name, check.Name)
+		checks[name] = strings.ReplaceAll(cond, "\n", " ")
+		names = append(names, name)
+	}
+
+	for _, column := range model.Columns {
+		if column.TypeColumn != jdb.COLUMN || column.TypeData != jdb.ENUM || len(column.Values) == 0 {
+			continue
+		}
+
+		name := fmt.Sprintf("chk_%s_%s_enum", model.Name, column.Name)
+		checks[name] = fmt.Sprintf("%s IN (%s)", column.Name, strs.JoinQuoted(column.Values, ", "))
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return "", nil
+	}
+
+	comments, err := CheckComments(model.Db(), model.Schema, model.Name)
+	if err != nil {
+		return "", err
+	}
+
+	result := ""
+	for _, name := range names {
+		def := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s);", model.Table, name, checks[name])
+		sign := signature(def)
+		comment, exists := comments[name]
+		if exists && comment == sign {
+			continue
+		}
+
+		if exists {
+			result = strs.Append(result, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s;", model.Table, name), "\n")
+		}
+		result = strs.Append(result, def, "\n")
+		result = strs.Append(result, fmt.Sprintf("COMMENT ON CONSTRAINT %s ON %s IS '%s';", name, model.Table, sign), "\n")
+	}
+
+	return result, nil
+}
+
+/**
+* buildMigrations
+* @param model *jdb.Model
+* @return (string, error) // definitions that can run on an existing table
+**/
+func (s *Driver) buildMigrations(model *jdb.Model) (string, error) {
+	result, err := s.buildIndexSpecs(model)
+	if err != nil {
+		return "", err
+	}
+
+	def, err := s.buildChecks(model)
+	if err != nil {
+		return "", err
+	}
+
+	if def != "" {
+		result = strs.Append(result, def, "\n")
+	}
+
+	return result, nil
+}
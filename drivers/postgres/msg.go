@@ -3,10 +3,14 @@ package postgres
 import "github.com/cgalvisleon/et/envar"
 
 var (
-	MSG_ATRIB_REQUIRED = "Atrib required (%s)"
-	MSG_CREATE_MODEL   = "Create model:%s v:%d"
-	MSG_MUTATE_MODEL   = "Mutate model:%s v:%d"
-	MSG_LOAD_MODEL     = "Load model:%s v:%d"
+	MSG_ATRIB_REQUIRED        = "Atrib required (%s)"
+	MSG_CREATE_MODEL          = "Create model:%s v:%d"
+	MSG_MUTATE_MODEL          = "Mutate model:%s v:%d"
+	MSG_LOAD_MODEL            = "Load model:%s v:%d"
+	MSG_EXTENSION_UNAVAILABLE = "Extension %s unavailable: %v"
+	MSG_POSTGIS_REQUIRED      = "PostGIS required to filter or sort by the geometry %s"
+	MSG_GEO_VALUE_REQUIRED    = "Geometry value required (%s)"
+	MSG_GEO_OPERATOR_INVALID  = "Invalid geometry operator (%s)"
 )
 
 func init() {
@@ -17,5 +21,9 @@ func init() {
 		MSG_CREATE_MODEL = "Crear modelo:%s v:%d"
 		MSG_MUTATE_MODEL = "Mutar modelo:%s v:%d"
 		MSG_LOAD_MODEL = "Cargar modelo:%s v:%d"
+		MSG_EXTENSION_UNAVAILABLE = "Extension %s no disponible: %v"
+		MSG_POSTGIS_REQUIRED = "PostGIS requerido para filtrar u ordenar por la geometria %s"
+		MSG_GEO_VALUE_REQUIRED = "Valor de geometria requerido (%s)"
+		MSG_GEO_OPERATOR_INVALID = "Operador de geometria invalido (%s)"
 	}
 }
@@ -3,9 +3,10 @@ package postgres
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/cgalvisleon/et/strs"
-	"github.com/cgalvisleon/et/utility"
 	"github.com/cgalvisleon/jql/jdb"
 )
 
@@ -15,6 +16,10 @@ import (
 * @return (string, error)
 **/
 func (s *Driver) buildQuery(ql *jdb.Ql) (string, error) {
+	if ql.TextSearch != nil && !jdb.ValidLanguage(ql.TextSearch.Language) {
+		return "", fmt.Errorf(jdb.MSG_LANGUAGE_INVALID, ql.TextSearch.Language)
+	}
+
 	sql, err := s.buildSelect(ql)
 	if err != nil {
 		return "", err
@@ -34,21 +39,39 @@ func (s *Driver) buildQuery(ql *jdb.Ql) (string, error) {
 	}
 
 	if def != "" {
-		def = fmt.Sprintf("JOIN %s", def)
 		sql = strs.Append(sql, def, "\n")
 	}
 
-	wheres := ql.Wheres.Conditions
-	if len(wheres) > 0 {
-		def, err = s.buildWhere(wheres)
-		if err != nil {
-			return "", err
-		}
+	def, err = s.buildWhere(ql.Wheres.Conditions)
+	if err != nil {
+		return "", err
+	}
 
-		if def != "" {
-			def = fmt.Sprintf("WHERE %s", def)
-			sql = strs.Append(sql, def, "\n")
-		}
+	scope, err := s.buildWhere(ql.Scope(ql.Froms[0]))
+	if err != nil {
+		return "", err
+	}
+
+	if def != "" && scope != "" {
+		def = fmt.Sprintf("(%s)\nAND %s", def, scope)
+	} else if scope != "" {
+		def = scope
+	}
+
+	keyset, err := s.buildKeyset(ql)
+	if err != nil {
+		return "", err
+	}
+
+	if def != "" && keyset != "" {
+		def = fmt.Sprintf("(%s)\nAND %s", def, keyset)
+	} else if keyset != "" {
+		def = keyset
+	}
+
+	if def != "" {
+		def = fmt.Sprintf("WHERE %s", def)
+		sql = strs.Append(sql, def, "\n")
 	}
 
 	def, err = s.buildGroupBy(ql)
@@ -90,6 +113,15 @@ func (s *Driver) buildQuery(ql *jdb.Ql) (string, error) {
 		sql = strs.Append(sql, def, "\n")
 	}
 
+	def, err = s.buildWith(ql)
+	if err != nil {
+		return "", err
+	}
+
+	if def != "" {
+		sql = strs.Append(def, sql, "\n")
+	}
+
 	if ql.Type == jdb.EXISTS {
 		return fmt.Sprintf("SELECT EXISTS(%s);", sql), nil
 	} else {
@@ -97,6 +129,115 @@ func (s *Driver) buildQuery(ql *jdb.Ql) (string, error) {
 	}
 }
 
+/**
+* buildWith
+* @param ql *jdb.Ql
+* @return (string, error)
+**/
+func (s *Driver) buildWith(ql *jdb.Ql) (string, error) {
+	result := ""
+	recursive := false
+	for _, cte := range ql.Ctes {
+		def, err := s.buildStatement(cte.Query)
+		if err != nil {
+			return "", err
+		}
+
+		if cte.Recursive != nil {
+			rec, err := s.buildStatement(cte.Recursive)
+			if err != nil {
+				return "", err
+			}
+			def = fmt.Sprintf("%s\nUNION ALL\n%s", def, rec)
+			recursive = true
+		}
+
+		def = fmt.Sprintf("%s AS (%s)", cte.Name, def)
+		result = strs.Append(result, def, ",\n")
+	}
+
+	if result == "" {
+		return "", nil
+	}
+
+	if recursive {
+		return fmt.Sprintf("WITH RECURSIVE %s", result), nil
+	}
+
+	return fmt.Sprintf("WITH %s", result), nil
+}
+
+type projection struct {
+	as  string
+	def string
+}
+
+/**
+* addProjection
+* @param items []projection, as, def string
+* @return []projection // in the order of the query, a repeated alias keeps the first projection
+**/
+func addProjection(items []projection, as, def string) []projection {
+	for _, item := range items {
+		if item.as == as {
+			return items
+		}
+	}
+
+	return append(items, projection{as: as, def: def})
+}
+
+/**
+* buildProjections
+* @param ql *jdb.Ql
+* @return []projection, bool, error // the selects of ql and its embedded joins, the attributes of DATA are apart, true when it has aggregates
+**/
+func (s *Driver) buildProjections(ql *jdb.Ql) ([]projection, bool, error) {
+	result := []projection{}
+	aggs := false
+	for _, fld := range ql.Selects {
+		switch fld.TypeColumn {
+		case jdb.COLUMN:
+			as := FieldAs(fld)
+			if s.fieldGeometry(fld) && ql.Type != jdb.DATA {
+				as = fmt.Sprintf("ST_AsGeoJSON(%s)::jsonb", as)
+			}
+			result = addProjection(result, fld.As, as)
+		case jdb.ATTRIB:
+			if ql.Type != jdb.DATA {
+				result = addProjection(result, fld.As, AttribAs(fld))
+			}
+		case jdb.AGG:
+			result = addProjection(result, fld.As, AggAs(fld))
+			aggs = true
+		case jdb.SUBQUERY:
+			as, err := s.buildValue(fld.Field)
+			if err != nil {
+				return nil, false, err
+			}
+			result = addProjection(result, fld.As, as)
+		}
+	}
+
+	return append(result, embedJoins(ql)...), aggs, nil
+}
+
+/**
+* embedJoins
+* @param ql *jdb.Ql
+* @return []projection // the rows of the embedded joins as objects
+**/
+func embedJoins(ql *jdb.Ql) []projection {
+	result := []projection{}
+	for _, join := range ql.Joins {
+		if join.Embed {
+			result = addProjection(result, fromAlias(join.To), fromJson(join.To, []string{}))
+		}
+	}
+
+	return result
+}
+
 /**
 * buildSelect
 * @param ql *jdb.Ql
@@ -114,88 +255,120 @@ func (s *Driver) buildSelect(ql *jdb.Ql) (string, error) {
 	result := ""
 	if ql.Type == jdb.DATA {
 		if len(ql.Selects) == 0 {
-			hiddens := ql.Froms[0].Hidden()
-			hiddens = append(hiddens, ql.Hiddens...)
-			hiddens = utility.Add(hiddens, jdb.SOURCE)
-
-			def := fmt.Sprintf("to_jsonb(A) - ARRAY[%s]", strs.JoinQuoted(hiddens, ", "))
-			result = strs.Append(result, def, "||")
+			result = fromJson(ql.Froms[0], ql.Hiddens)
+			if def := buildObject(embedJoins(ql)); def != "" {
+				result = fmt.Sprintf("%s||%s", result, def)
+			}
 		} else {
-			selects := map[string]string{}
-			atribs := map[string]string{}
+			selects, aggs, err := s.buildProjections(ql)
+			if err != nil {
+				return "", err
+			}
+
+			atribs := []projection{}
 			for _, fld := range ql.Selects {
-				if fld.TypeColumn == jdb.COLUMN {
-					as := FieldAs(fld)
-					selects[fld.As] = as
-				} else if fld.TypeColumn == jdb.AGG {
-					as := FieldAs(fld)
-					selects[fld.As] = as
-				} else if fld.TypeColumn == jdb.ATTRIB {
-					as := FieldAs(fld)
-					atribs[fld.As] = as
+				if fld.TypeColumn == jdb.ATTRIB {
+					atribs = addProjection(atribs, fld.As, AttribAs(fld))
 				}
 			}
 
-			if len(atribs) == 0 {
-				result = fmt.Sprintf("\n%s", jdb.SOURCE)
+			if len(atribs) == 0 && aggs {
+				result = "\n'{}'::jsonb"
+			} else if len(atribs) == 0 {
+				result = fmt.Sprintf("\n%s.%s", fromAlias(ql.Froms[0]), jdb.SOURCE)
 			} else {
-				for k, v := range atribs {
-					def := fmt.Sprintf("\n'%s', %s", k, v)
-					result = strs.Append(result, def, ", ")
-				}
-
-				if result != "" {
-					result = fmt.Sprintf("\n\tjsonb_build_object(%s\n)", result)
-				}
+				result = fmt.Sprintf("\n\t%s", buildObject(atribs))
 			}
 
-			sel := ""
-			for k, v := range selects {
-				def := fmt.Sprintf("\n'%s',  %s", k, v)
-				if v == "" {
-					def = fmt.Sprintf("\n'%s',  %s", k, k)
-				}
-				sel = strs.Append(sel, def, ", ")
+			if def := buildObject(selects); def != "" {
+				result = fmt.Sprintf("%s||%s", result, def)
 			}
+		}
 
-			if sel != "" {
-				result = fmt.Sprintf("%s||jsonb_build_object(%s\n)", result, sel)
-			}
+		if def := buildObject(searchSelects(ql)); def != "" {
+			result = fmt.Sprintf("%s||%s", result, def)
 		}
 
 		return fmt.Sprintf("%s AS result", result), nil
 	}
 
+	extra := searchSelects(ql)
 	if len(ql.Selects) == 0 {
 		hiddens := ql.Froms[0].Hidden()
 		hiddens = append(hiddens, ql.Hiddens...)
-		if len(hiddens) > 0 {
-			result += fmt.Sprintf("to_jsonb(A) - ARRAY[%s]", strs.JoinQuoted(hiddens, ", "))
+		joins := embedJoins(ql)
+		if len(hiddens) > 0 || len(joins) > 0 {
+			result += fromJson(ql.Froms[0], ql.Hiddens)
+			if def := buildObject(append(joins, extra...)); def != "" {
+				result = fmt.Sprintf("%s||%s", result, def)
+			}
+			extra = []projection{}
 		} else {
-			result += "A.*"
+			result += fmt.Sprintf("%s.*", fromAlias(ql.Froms[0]))
 		}
 	} else {
-		selects := map[string]string{}
-		for _, fld := range ql.Selects {
-			if fld.TypeColumn == jdb.COLUMN {
-				as := FieldAs(fld)
-				selects[fld.As] = as
-			}
+		selects, _, err := s.buildProjections(ql)
+		if err != nil {
+			return "", err
 		}
-		for k, v := range selects {
-			def := fmt.Sprintf("\n%s AS %s", v, k)
-			if k == v {
-				def = fmt.Sprintf("\n%s", v)
-			} else if v == "" {
-				def = fmt.Sprintf("\n%s", v)
+		for _, item := range selects {
+			def := fmt.Sprintf("\n%s AS %s", item.def, item.as)
+			if item.as == item.def || item.def == "" {
+				def = fmt.Sprintf("\n%s", item.def)
 			}
 			result = strs.Append(result, def, ", ")
 		}
 	}
 
+	for _, item := range extra {
+		result = strs.Append(result, fmt.Sprintf("\n%s AS %s", item.def, item.as), ", ")
+	}
+
 	return result, nil
 }
 
+/**
+* buildObject
+* @param fields []projection
+* @return string
+**/
+func buildObject(fields []projection) string {
+	result := ""
+	for _, item := range fields {
+		def := fmt.Sprintf("\n'%s', %s", item.as, item.def)
+		result = strs.Append(result, def, ", ")
+	}
+
+	if result == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("jsonb_build_object(%s\n)", result)
+}
+
+/**
+* fromTable
+* @param from *jdb.From
+* @return (string, error)
+**/
+func (s *Driver) fromTable(from *jdb.From) (string, error) {
+	as := from.As
+	table := from.Table
+	if from.Query != nil {
+		sub, err := s.buildSubquery(from.Query)
+		if err != nil {
+			return "", err
+		}
+		table = sub
+	}
+
+	if as == table || as == "" {
+		return table, nil
+	}
+
+	return fmt.Sprintf("%s AS %s", table, as), nil
+}
+
 /**
 * buildFrom
 * @param ql *jdb.Ql
@@ -208,16 +381,22 @@ func (s *Driver) buildFrom(ql *jdb.Ql) (string, error) {
 		return result, errors.New(jdb.MSG_FROM_REQUIRED)
 	}
 
+	joined := map[*jdb.From]bool{}
+	for _, join := range ql.Joins {
+		joined[join.To] = true
+	}
+
 	for _, from := range ql.Froms {
-		as := from.As
-		table := from.Table
-		def := strs.Append(table, as, " AS ")
-		if as == table {
-			def = table
+		if joined[from] {
+			continue
+		}
+
+		def, err := s.fromTable(from)
+		if err != nil {
+			return "", err
 		}
 
 		result = strs.Append(result, def, ", ")
-		break
 	}
 
 	return result, nil
@@ -236,73 +415,227 @@ func (s *Driver) buildJoins(ql *jdb.Ql) (string, error) {
 	}
 
 	for _, join := range ql.Joins {
-		def := ""
-		for k, v := range join.Keys {
-			if len(def) == 0 {
-				def = fmt.Sprintf("%s AS %s ON %s = %s", join.To.As, join.To.As, k, v)
-			} else {
-				def = fmt.Sprintf("%s AND %s = %s", def, k, v)
-			}
+		table, err := s.fromTable(join.To)
+		if err != nil {
+			return "", err
 		}
 
-		if join.Type == jdb.LEFT {
-			result = strs.Append(result, def, "\nLEFT JOIN ")
-		} else if join.Type == jdb.RIGHT {
-			result = strs.Append(result, def, "\nRIGHT JOIN ")
-		} else if join.Type == jdb.FULL {
-			result = strs.Append(result, def, "\nFULL JOIN ")
-		} else {
-			result = strs.Append(result, def, "\nJOIN ")
+		on, err := s.buildWhere(append(join.On, ql.Scope(join.To)...))
+		if err != nil {
+			return "", err
+		}
+
+		def := fmt.Sprintf("%s ON %s", table, strings.ReplaceAll(on, "\n", " "))
+		switch join.Type {
+		case jdb.LEFT:
+			def = fmt.Sprintf("LEFT JOIN %s", def)
+		case jdb.RIGHT:
+			def = fmt.Sprintf("RIGHT JOIN %s", def)
+		case jdb.FULL:
+			def = fmt.Sprintf("FULL JOIN %s", def)
+		default:
+			def = fmt.Sprintf("JOIN %s", def)
+		}
+
+		result = strs.Append(result, def, "\n")
+	}
+
+	return result, nil
+}
+
+/**
+* buildSubquery
+* @param ql *jdb.Ql
+* @return (string, error)
+**/
+func (s *Driver) buildSubquery(ql *jdb.Ql) (string, error) {
+	result, err := s.buildStatement(ql)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("(%s)", result), nil
+}
+
+/**
+* buildStatement
+* @param ql *jdb.Ql
+* @return (string, error) // the query of a subquery, without terminator
+**/
+func (s *Driver) buildStatement(ql *jdb.Ql) (string, error) {
+	sub := *ql
+	if sub.Type == jdb.DATA {
+		sub.Type = jdb.SELECT
+	}
+
+	result, err := s.buildQuery(&sub)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(result, ";"), nil
+}
+
+/**
+* buildValue
+* @param value any
+* @return (string, error)
+**/
+func (s *Driver) buildValue(value any) (string, error) {
+	switch v := value.(type) {
+	case *jdb.Ql:
+		return s.buildSubquery(v)
+	case *jdb.Field:
+		return s.fieldExpr(v)
+	default:
+		return fmt.Sprintf("%v", jdb.Quoted(value)), nil
+	}
+}
+
+/**
+* buildList
+* @param value any
+* @return (string, error)
+**/
+func (s *Driver) buildList(value any) (string, error) {
+	if ql, ok := value.(*jdb.Ql); ok {
+		return s.buildSubquery(ql)
+	}
+
+	val := reflect.ValueOf(value)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return s.buildValue(value)
+	}
+
+	result := ""
+	for i := 0; i < val.Len(); i++ {
+		def := fmt.Sprintf("%v", jdb.Quoted(val.Index(i).Interface()))
+		result = strs.Append(result, def, ", ")
+	}
+
+	if result == "" {
+		result = "NULL"
+	}
+
+	return fmt.Sprintf("(%s)", result), nil
+}
+
+/**
+* attribValue
+* @param value any
+* @return any // attributes are compared as text
+**/
+func attribValue(value any) any {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+		return fmt.Sprintf("%v", v)
+	default:
+		return value
+	}
+}
+
+/**
+* betweenValues
+* @param value any
+* @return (any, any)
+**/
+func betweenValues(value any) (any, any) {
+	switch v := value.(type) {
+	case jdb.BetweenValue:
+		return v.Min, v.Max
+	case *jdb.BetweenValue:
+		return v.Min, v.Max
+	case []interface{}:
+		if len(v) == 2 {
+			return v[0], v[1]
 		}
 	}
 
-	return fmt.Sprintf("%s", result), nil
+	return nil, nil
 }
 
 /**
 * buildCondition
 * @param cond *jdb.Condition
-* @return string
+* @return (string, error)
 **/
-func (s *Driver) buildCondition(cond *jdb.Condition) string {
-	key := FieldAs(cond.Field)
-	value := jdb.Quoted(cond.Value)
+func (s *Driver) buildCondition(cond *jdb.Condition) (string, error) {
+	key, err := s.fieldExpr(cond.Field)
+	if err != nil {
+		return "", err
+	}
+
+	switch cond.Operator {
+	case jdb.OpExists, jdb.OpNotExists:
+		ql, ok := cond.Value.(*jdb.Ql)
+		if !ok {
+			return "", fmt.Errorf(jdb.MSG_ATTRIBUTE_REQUIRED, "query")
+		}
+		sub, err := s.buildSubquery(ql)
+		if err != nil {
+			return "", err
+		}
+		if cond.Operator == jdb.OpNotExists {
+			return fmt.Sprintf("NOT EXISTS%s", sub), nil
+		}
+		return fmt.Sprintf("EXISTS%s", sub), nil
+	case jdb.OpIn, jdb.OpNotIn:
+		list, err := s.buildList(cond.Value)
+		if err != nil {
+			return "", err
+		}
+		if cond.Operator == jdb.OpNotIn {
+			return fmt.Sprintf("%s NOT IN %s", key, list), nil
+		}
+		return fmt.Sprintf("%s IN %s", key, list), nil
+	case jdb.OpNull:
+		return fmt.Sprintf("%s IS NULL", key), nil
+	case jdb.OpNotNull:
+		return fmt.Sprintf("%s IS NOT NULL", key), nil
+	case jdb.OpWithin, jdb.OpIntersects, jdb.OpDWithin:
+		return s.buildGeoCondition(cond)
+	case jdb.OpSearch, jdb.OpILike, jdb.OpSimilar:
+		return s.buildSearchCondition(cond)
+	case jdb.OpBetween, jdb.OpNotBetween:
+		min, max := betweenValues(cond.Value)
+		if cond.Operator == jdb.OpNotBetween {
+			return fmt.Sprintf("%s NOT BETWEEN %v AND %v", key, jdb.Quoted(min), jdb.Quoted(max)), nil
+		}
+		return fmt.Sprintf("%s BETWEEN %v AND %v", key, jdb.Quoted(min), jdb.Quoted(max)), nil
+	}
+
+	val := cond.Value
+	if cond.Field.TypeColumn == jdb.ATTRIB {
+		val = attribValue(val)
+	}
+
+	value, err := s.buildValue(val)
+	if err != nil {
+		return "", err
+	}
+
 	switch cond.Operator {
 	case jdb.OpEq:
-		return fmt.Sprintf("%s = %v", key, value)
+		return fmt.Sprintf("%s = %s", key, value), nil
 	case jdb.OpNeg:
-		return fmt.Sprintf("%s != %v", key, value)
+		return fmt.Sprintf("%s != %s", key, value), nil
 	case jdb.OpLess:
-		return fmt.Sprintf("%s < %v", key, value)
+		return fmt.Sprintf("%s < %s", key, value), nil
 	case jdb.OpLessEq:
-		return fmt.Sprintf("%s <= %v", key, value)
+		return fmt.Sprintf("%s <= %s", key, value), nil
 	case jdb.OpMore:
-		return fmt.Sprintf("%s > %v", key, value)
+		return fmt.Sprintf("%s > %s", key, value), nil
 	case jdb.OpMoreEq:
-		return fmt.Sprintf("%s >= %v", key, value)
+		return fmt.Sprintf("%s >= %s", key, value), nil
 	case jdb.OpLike:
-		return fmt.Sprintf("%s LIKE %v", key, value)
-	case jdb.OpIn:
-		return fmt.Sprintf("%s IN %v", key, value)
-	case jdb.OpNotIn:
-		return fmt.Sprintf("%s NOT IN %v", key, value)
+		return fmt.Sprintf("%s LIKE %s", key, value), nil
 	case jdb.OpIs:
-		return fmt.Sprintf("%s IS %v", key, value)
+		return fmt.Sprintf("%s IS %s", key, value), nil
 	case jdb.OpIsNot:
-		return fmt.Sprintf("%s IS NOT %v", key, value)
-	case jdb.OpNull:
-		return fmt.Sprintf("%s IS NULL", key)
-	case jdb.OpNotNull:
-		return fmt.Sprintf("%s IS NOT NULL", key)
-	case jdb.OpBetween:
-		vals := cond.Value.([]interface{})
-		return fmt.Sprintf("%s BETWEEN %v AND %v", key, jdb.Quoted(vals[0]), jdb.Quoted(vals[1]))
-	case jdb.OpNotBetween:
-		vals := cond.Value.([]interface{})
-		return fmt.Sprintf("%s NOT BETWEEN %v AND %v", key, jdb.Quoted(vals[0]), jdb.Quoted(vals[1]))
+		return fmt.Sprintf("%s IS NOT %s", key, value), nil
 	}
 
-	return ""
+	return "", nil
 }
 
 /**
@@ -314,12 +647,17 @@ func (s *Driver) buildWhere(wheres []*jdb.Condition) (string, error) {
 	result := ""
 
 	for i, cond := range wheres {
+		def, err := s.buildCondition(cond)
+		if err != nil {
+			return "", err
+		}
+
 		if i == 0 {
-			result = s.buildCondition(cond)
+			result = def
 		} else if cond.Connector == jdb.OR {
-			result = fmt.Sprintf("%s\nOR %s", result, s.buildCondition(cond))
+			result = fmt.Sprintf("%s\nOR %s", result, def)
 		} else {
-			result = fmt.Sprintf("%s\nAND %s", result, s.buildCondition(cond))
+			result = fmt.Sprintf("%s\nAND %s", result, def)
 		}
 	}
 
@@ -353,24 +691,41 @@ func (s *Driver) buildGroupBy(ql *jdb.Ql) (string, error) {
 * @return (string, error)
 **/
 func (s *Driver) buildOrderBy(ql *jdb.Ql) (string, error) {
-	asc := ""
-	desc := ""
-	for _, order := range ql.OrdersBy {
-		as := FieldAs(order.Field)
-		if order.Asc {
-			asc = strs.Append(asc, as, ", ")
-		} else {
-			desc = strs.Append(desc, as, ", ")
-		}
+	result := ""
+	if ql.Type == jdb.COUNTED || ql.Type == jdb.EXISTS {
+		return result, nil
 	}
 
-	result := ""
-	if asc != "" {
-		result = fmt.Sprintf(`%s ASC`, asc)
+	orders := ql.OrdersBy
+	if ql.Cursor != nil {
+		orders = ql.KeysetOrders()
+	} else if ql.TextSearch != nil && ql.TextSearch.Rank {
+		result = fmt.Sprintf("ts_rank(%s, %s) DESC", FieldAs(ql.TextSearch.Field), tsQuery(ql.TextSearch))
 	}
 
-	if desc != "" {
-		result = fmt.Sprintf(`%s DESC`, desc)
+	for _, order := range orders {
+		as, err := s.fieldExpr(order.Field)
+		if err != nil {
+			return "", err
+		}
+		if order.Nearest != nil {
+			key, err := s.geometryKey(order.Field)
+			if err != nil {
+				return "", err
+			}
+			as = fmt.Sprintf("%s <-> %s", key, geometry(order.Nearest, srid(order.Field.Column())))
+		}
+
+		asc := order.Asc
+		if ql.Cursor != nil && ql.Cursor.Before {
+			asc = !asc
+		}
+
+		def := fmt.Sprintf(`%s ASC`, as)
+		if !asc {
+			def = fmt.Sprintf(`%s DESC`, as)
+		}
+		result = strs.Append(result, def, ", ")
 	}
 
 	return result, nil
@@ -396,6 +751,45 @@ func (s *Driver) buildLimit(ql *jdb.Ql) (string, error) {
 	}
 
 	offset := (ql.Page - 1) * ql.Rows
-	result = fmt.Sprintf("%d OFFSET %d", ql.Rows, offset)
+	result = fmt.Sprintf("LIMIT %d OFFSET %d", ql.Rows, offset)
 	return result, nil
 }
+
+/**
+* buildKeyset
+* @param ql *jdb.Ql
+* @return (string, error)
+**/
+func (s *Driver) buildKeyset(ql *jdb.Ql) (string, error) {
+	if ql.Cursor == nil || len(ql.Cursor.Values) == 0 {
+		return "", nil
+	}
+
+	if ql.Type == jdb.COUNTED || ql.Type == jdb.EXISTS {
+		return "", nil
+	}
+
+	orders, values, err := ql.KeysetValues()
+	if err != nil {
+		return "", err
+	}
+
+	result := ""
+	for i, order := range orders {
+		op := ">"
+		if order.Asc == ql.Cursor.Before {
+			op = "<"
+		}
+
+		def := ""
+		for j := 0; j < i; j++ {
+			eq := fmt.Sprintf("%s = %v", FieldAs(orders[j].Field), jdb.Quoted(values[j]))
+			def = strs.Append(def, eq, " AND ")
+		}
+		cond := fmt.Sprintf("%s %s %v", FieldAs(order.Field), op, jdb.Quoted(values[i]))
+		def = strs.Append(def, cond, " AND ")
+		result = strs.Append(result, fmt.Sprintf("(%s)", def), "\nOR ")
+	}
+
+	return fmt.Sprintf("(%s)", result), nil
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/cgalvisleon/et/strs"
+	"github.com/cgalvisleon/jql/jdb"
+)
+
+var searchWeights = []string{"A", "B", "C", "D"}
+
+/**
+* FieldKey
+* @param field *jdb.Field
+* @return string
+**/
+func FieldKey(field *jdb.Field) string {
+	if field.TypeColumn != jdb.ATTRIB {
+		return FieldAs(field)
+	}
+
+	name, ok := field.Field.(string)
+	if !ok {
+		return FieldAs(field)
+	}
+
+	source := jdb.SOURCE
+	if field.From != nil && field.From.As != "" {
+		source = fmt.Sprintf("%s.%s", field.From.As, source)
+	}
+
+	return fmt.Sprintf("(%s->>'%s')", source, name)
+}
+
+/**
+* buildSearchColumn
+* @param model *jdb.Model, column *jdb.Column
+* @return string
+**/
+func (s *Driver) buildSearchColumn(model *jdb.Model, column *jdb.Column) string {
+	language := model.SearchLanguage
+	vector := ""
+	for i, name := range model.SearchFields {
+		col := model.FindColumn(name)
+		if col == nil {
+			continue
+		}
+
+		value := fmt.Sprintf("COALESCE(%s::text, '')", name)
+		if col.TypeColumn == jdb.ATTRIB {
+			value = fmt.Sprintf("COALESCE(%s->>'%s', '')", jdb.SOURCE, name)
+		}
+
+		weight := searchWeights[min(i, len(searchWeights)-1)]
+		def := fmt.Sprintf("setweight(to_tsvector(%v, %s), '%s')", jdb.Quoted(language), value, weight)
+		vector = strs.Append(vector, def, " || ")
+	}
+
+	if vector == "" {
+		vector = fmt.Sprintf("to_tsvector(%v, '')", jdb.Quoted(language))
+	}
+
+	return fmt.Sprintf("\n\t%s TSVECTOR GENERATED ALWAYS AS (%s) STORED", column.Name, vector)
+}
+
+/**
+* buildSearchIndexes
+* @param model *jdb.Model
+* @return string
+**/
+func (s *Driver) buildSearchIndexes(model *jdb.Model) string {
+	if !s.trgm {
+		return ""
+	}
+
+	result := ""
+	for _, name := range model.SearchFields {
+		col := model.FindColumn(name)
+		if col == nil || col.TypeColumn != jdb.COLUMN {
+			continue
+		}
+
+		def := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_%s_trgm ON %s USING GIN ((%s::text) gin_trgm_ops);", model.Name, name, model.Table, name)
+		result = strs.Append(result, def, "\n")
+	}
+
+	return result
+}
+
+/**
+* tsQuery
+* @param search *jdb.Search
+* @return string
+**/
+func tsQuery(search *jdb.Search) string {
+	return fmt.Sprintf("websearch_to_tsquery(%v, %v)", jdb.Quoted(search.Language), jdb.Quoted(search.Text))
+}
+
+/**
+* buildSearchCondition
+* @param cond *jdb.Condition
+* @return string, error
+**/
+func (s *Driver) buildSearchCondition(cond *jdb.Condition) (string, error) {
+	key := FieldKey(cond.Field)
+	switch cond.Operator {
+	case jdb.OpSearch:
+		search, ok := cond.Value.(*jdb.Search)
+		if !ok {
+			return "", fmt.Errorf(jdb.MSG_ATTRIBUTE_REQUIRED, "search")
+		}
+		if !jdb.ValidLanguage(search.Language) {
+			return "", fmt.Errorf(jdb.MSG_LANGUAGE_INVALID, search.Language)
+		}
+		return fmt.Sprintf("%s @@ %s", key, tsQuery(search)), nil
+	case jdb.OpILike:
+		return fmt.Sprintf("%s::text ILIKE %v", key, jdb.Quoted(cond.Value)), nil
+	case jdb.OpSimilar:
+		return fmt.Sprintf("%s::text %% %v", key, jdb.Quoted(cond.Value)), nil
+	}
+
+	return "", fmt.Errorf(jdb.MSG_COMMAND_INVALID, cond.Operator)
+}
+
+/**
+* searchSelects
+* @param ql *jdb.Ql
+* @return []projection
+**/
+func searchSelects(ql *jdb.Ql) []projection {
+	result := []projection{}
+	search := ql.TextSearch
+	if search == nil {
+		return result
+	}
+
+	query := tsQuery(search)
+	if search.Rank {
+		result = addProjection(result, "rank", fmt.Sprintf("ts_rank(%s, %s)", FieldAs(search.Field), query))
+	}
+
+	for _, fld := range search.Highlight {
+		as := fmt.Sprintf("%s_highlight", fld.As)
+		result = addProjection(result, as, fmt.Sprintf("ts_headline(%v, %s::text, %s)", jdb.Quoted(search.Language), FieldKey(fld), query))
+	}
+
+	return result
+}
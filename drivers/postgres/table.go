@@ -31,3 +31,81 @@ func ExistTable(db *sql.DB, schema, name string) (bool, error) {
 
 	return items.Bool(0, "exists"), nil
 }
+
+/**
+* ColumnTypes
+* @param db *sql.DB, schema, name string
+* @return map[string]string, error // the udt name of each column of the table
+**/
+func ColumnTypes(db *sql.DB, schema, name string) (map[string]string, error) {
+	rows, err := db.Query(`
+	SELECT column_name, udt_name
+	FROM information_schema.columns
+	WHERE UPPER(table_schema) = UPPER($1)
+	AND UPPER(table_name) = UPPER($2);`, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := jdb.RowsToItems(rows)
+	result := make(map[string]string)
+	for _, item := range items.Result {
+		result[item.Str("column_name")] = item.Str("udt_name")
+	}
+
+	return result, nil
+}
+
+/**
+* IndexComments
+* @param db *sql.DB, schema, name string
+* @return map[string]string, error // the comment of each index of the table
+**/
+func IndexComments(db *sql.DB, schema, name string) (map[string]string, error) {
+	rows, err := db.Query(`
+	SELECT indexname, COALESCE(obj_description(format('%I.%I', schemaname, indexname)::regclass, 'pg_class'), '') AS comment
+	FROM pg_indexes
+	WHERE UPPER(schemaname) = UPPER($1)
+	AND UPPER(tablename) = UPPER($2);`, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := jdb.RowsToItems(rows)
+	result := make(map[string]string)
+	for _, item := range items.Result {
+		result[item.Str("indexname")] = item.Str("comment")
+	}
+
+	return result, nil
+}
+
+/**
+* CheckComments
+* @param db *sql.DB, schema, name string
+* @return map[string]string, error // the comment of each check constraint of the table
+**/
+func CheckComments(db *sql.DB, schema, name string) (map[string]string, error) {
+	rows, err := db.Query(`
+	SELECT c.conname, COALESCE(obj_description(c.oid, 'pg_constraint'), '') AS comment
+	FROM pg_constraint c
+	JOIN pg_class t ON t.oid = c.conrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	WHERE c.contype = 'c'
+	AND UPPER(n.nspname) = UPPER($1)
+	AND UPPER(t.relname) = UPPER($2);`, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := jdb.RowsToItems(rows)
+	result := make(map[string]string)
+	for _, item := range items.Result {
+		result[item.Str("conname")] = item.Str("comment")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,339 @@
+package jdb
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cgalvisleon/et/envar"
+	"github.com/cgalvisleon/et/et"
+	"github.com/cgalvisleon/et/strs"
+	"github.com/cgalvisleon/et/timezone"
+	"github.com/cgalvisleon/et/utility"
+)
+
+type Cache interface {
+	Get(key string) (et.Items, bool)
+	Set(key string, value et.Items, ttl time.Duration, tags []string)
+	Invalidate(tag string)
+}
+
+type cacheEntry struct {
+	key     string
+	value   et.Items
+	expires time.Time
+	tags    []string
+}
+
+type MemoryCache struct {
+	size  int
+	order *list.List
+	items map[string]*list.Element
+	tags  map[string]map[string]bool
+	mu    sync.Mutex
+}
+
+/**
+* NewMemoryCache
+* @param size int // max entries, the least recently used are evicted
+* @return *MemoryCache
+**/
+func NewMemoryCache(size int) *MemoryCache {
+	if size <= 0 {
+		size = 1000
+	}
+
+	return &MemoryCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+		tags:  make(map[string]map[string]bool),
+	}
+}
+
+/**
+* Get
+* @param key string
+* @return et.Items, bool
+**/
+func (s *MemoryCache) Get(key string) (et.Items, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.items[key]
+	if !ok {
+		return et.Items{}, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if timezone.Now().After(entry.expires) {
+		s.remove(elem)
+		return et.Items{}, false
+	}
+
+	s.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+/**
+* Set
+* @param key string, value et.Items, ttl time.Duration, tags []string
+**/
+func (s *MemoryCache) Set(key string, value et.Items, ttl time.Duration, tags []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.items[key]
+	if ok {
+		s.remove(elem)
+	}
+
+	entry := &cacheEntry{
+		key:     key,
+		value:   value,
+		expires: timezone.Now().Add(ttl),
+		tags:    tags,
+	}
+	s.items[key] = s.order.PushFront(entry)
+	for _, tag := range tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			keys = make(map[string]bool)
+			s.tags[tag] = keys
+		}
+		keys[key] = true
+	}
+
+	for s.order.Len() > s.size {
+		s.remove(s.order.Back())
+	}
+}
+
+/**
+* Invalidate
+* @param tag string
+**/
+func (s *MemoryCache) Invalidate(tag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.tags[tag] {
+		elem, ok := s.items[key]
+		if ok {
+			s.remove(elem)
+		}
+	}
+	delete(s.tags, tag)
+}
+
+/**
+* remove
+* @param elem *list.Element
+**/
+func (s *MemoryCache) remove(elem *list.Element) {
+	entry := elem.Value.(*cacheEntry)
+	s.order.Remove(elem)
+	delete(s.items, entry.key)
+	for _, tag := range entry.tags {
+		keys, ok := s.tags[tag]
+		if !ok {
+			continue
+		}
+
+		delete(keys, entry.key)
+		if len(keys) == 0 {
+			delete(s.tags, tag)
+		}
+	}
+}
+
+var (
+	cache        Cache = NewMemoryCache(envar.GetInt("CACHE_SIZE", 1000))
+	invalidators []func(model string)
+	cacheMu      sync.RWMutex
+)
+
+/**
+* SetCache
+* @param value Cache // replaces the in-memory cache, a shared cache for several instances
+**/
+func SetCache(value Cache) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	cache = value
+}
+
+/**
+* getCache
+* @return Cache
+**/
+func getCache() Cache {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+
+	return cache
+}
+
+/**
+* OnInvalidate
+* @param fn func(model string) // called when a local command invalidates a model, to publish the event to other instances
+**/
+func OnInvalidate(fn func(model string)) {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	invalidators = append(invalidators, fn)
+}
+
+/**
+* InvalidateModel
+* @param model string // the key of the model, to apply the events of other instances
+**/
+func InvalidateModel(model string) {
+	result := getCache()
+	if result == nil {
+		return
+	}
+
+	result.Invalidate(model)
+}
+
+/**
+* invalidate
+* @param model string
+**/
+func invalidate(model string) {
+	InvalidateModel(model)
+
+	cacheMu.RLock()
+	fns := invalidators
+	cacheMu.RUnlock()
+	for _, fn := range fns {
+		fn(model)
+	}
+}
+
+/**
+* fromKey
+* @param from *From
+* @return string
+**/
+func fromKey(from *From) string {
+	result := from.Name
+	result = strs.Append(from.Schema, result, ".")
+	result = strs.Append(from.Database, result, ".")
+	return result
+}
+
+/**
+* Cache
+* @param ttl time.Duration // results outside a transaction are cached until ttl or a command on its models
+* @return *Ql
+**/
+func (s *Ql) Cache(ttl time.Duration) *Ql {
+	s.ttl = ttl
+	return s
+}
+
+/**
+* cacheTags
+* @return []string // the models read by the query
+**/
+func (s *Ql) cacheTags() []string {
+	result := make([]string, 0)
+	add := func(from *From) {
+		if from == nil || from.Query != nil || from.Name == "" {
+			return
+		}
+
+		result = utility.Add(result, fromKey(from))
+	}
+
+	for _, from := range s.Froms {
+		add(from)
+	}
+	for _, join := range s.Joins {
+		add(join.To)
+	}
+	for _, details := range []map[string]*Detail{s.Details, s.Rollups} {
+		for _, detail := range details {
+			add(detail.To)
+			add(detail.Through)
+		}
+	}
+	for _, ql := range s.nested() {
+		for _, tag := range ql.cacheTags() {
+			result = utility.Add(result, tag)
+		}
+	}
+
+	return result
+}
+
+/**
+* cacheKey
+* @param sql string
+* @return string // empty when the query is not cached
+**/
+func (s *Ql) cacheKey(sql string) string {
+	if s.ttl <= 0 || s.tx != nil || getCache() == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%x", s.db.Name, sha256.Sum256([]byte(sql)))
+}
+
+/**
+* fromCache
+* @param key string
+* @return et.Items, bool
+**/
+func (s *Ql) fromCache(key string) (et.Items, bool) {
+	if key == "" {
+		return et.Items{}, false
+	}
+
+	items, ok := getCache().Get(key)
+	if !ok {
+		return et.Items{}, false
+	}
+
+	return copyItems(items), true
+}
+
+/**
+* toCache
+* @param key string, items et.Items
+**/
+func (s *Ql) toCache(key string, items et.Items) {
+	if key == "" {
+		return
+	}
+
+	getCache().Set(key, copyItems(items), s.ttl, s.cacheTags())
+}
+
+/**
+* copyItems
+* @param items et.Items
+* @return et.Items // the rows are copied, callers can not change the cached rows
+**/
+func copyItems(items et.Items) et.Items {
+	result := et.Items{
+		Ok:     items.Ok,
+		Count:  items.Count,
+		Result: make([]et.Json, 0, len(items.Result)),
+	}
+	for _, item := range items.Result {
+		row := et.Json{}
+		for k, v := range item {
+			row[k] = v
+		}
+		result.Result = append(result.Result, row)
+	}
+
+	return result
+}
@@ -3,7 +3,9 @@ package jdb
 import (
 	"encoding/json"
 	"errors"
+	"time"
 
+	"github.com/cgalvisleon/et/envar"
 	"github.com/cgalvisleon/et/et"
 	"github.com/cgalvisleon/et/timezone"
 )
@@ -112,14 +114,13 @@ func versionCatalog(tp, name string) (int, error) {
 		And(Eq("name", name)).
 		Select("version").
 		One()
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
 	if err != nil {
 		return 0, err
 	}
 
-	if !item.Ok {
-		return 0, nil
-	}
-
 	return item.Int("version"), nil
 }
 
@@ -133,15 +134,15 @@ func getCatalog(tp, name string, dest any) (bool, error) {
 		Where(Eq("A.type", tp)).
 		And(Eq("A.name", name)).
 		Select().
+		Cache(time.Duration(envar.GetInt("CACHE_CATALOG", 60)) * time.Second).
 		One()
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
-	if !item.Ok {
-		return false, nil
-	}
-
 	bt, err := item.Byte("definition")
 	if err != nil {
 		return false, err
@@ -161,6 +162,10 @@ func getCatalog(tp, name string, dest any) (bool, error) {
 * @return error
 **/
 func deleteCatalog(tp, name string) error {
+	if catalog == nil {
+		return nil
+	}
+
 	_, err := catalog.
 		Delete().
 		Where(Eq("type", tp)).
@@ -0,0 +1,319 @@
+package jdb
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cgalvisleon/et/et"
+	"github.com/cgalvisleon/et/utility"
+)
+
+var StatusValues = []string{ACTIVE, ARCHIVED, CANCELED, OF_SYSTEM, FOR_DELETE, PENDING, APPROVED, REJECTED}
+
+type Check struct {
+	Name       string       `json:"name"`
+	Conditions []*Condition `json:"conditions"`
+}
+
+/**
+* Fields
+* @return []*Field
+**/
+func (s *Check) Fields() []*Field {
+	result := []*Field{}
+	for _, cond := range s.Conditions {
+		result = append(result, cond.Field)
+		if fld, ok := cond.Value.(*Field); ok {
+			result = append(result, fld)
+		}
+	}
+
+	return result
+}
+
+/**
+* IsColumn
+* @return bool // false when the check uses attributes, these are only checked by the validation
+**/
+func (s *Check) IsColumn() bool {
+	for _, fld := range s.Fields() {
+		if fld.TypeColumn != COLUMN {
+			return false
+		}
+	}
+
+	return true
+}
+
+/**
+* checkField
+* @param field *Field
+* @return *Field, error
+**/
+func (s *Model) checkField(field *Field) (*Field, error) {
+	name, ok := field.Field.(string)
+	if !ok {
+		return nil, fmt.Errorf(MSG_FIELD_NOT_FOUND, field.As)
+	}
+
+	column := s.FindColumn(name)
+	if column == nil || (column.TypeColumn != COLUMN && column.TypeColumn != ATTRIB) {
+		return nil, fmt.Errorf(MSG_FIELD_NOT_FOUND, name)
+	}
+
+	return &Field{
+		TypeColumn: column.TypeColumn,
+		Field:      column.Name,
+		As:         column.Name,
+	}, nil
+}
+
+/**
+* DefineCheck
+* @param name string, conditions ...*Condition // joined with AND unless the condition is an OR
+* @return error
+**/
+func (s *Model) DefineCheck(name string, conditions ...*Condition) error {
+	if !utility.ValidStr(name, 0, []string{}) {
+		return errors.New(MSG_NAME_REQUIRED)
+	}
+
+	if len(conditions) == 0 {
+		return fmt.Errorf(MSG_ATTRIBUTE_REQUIRED, "condition")
+	}
+
+	check := &Check{Name: name}
+	for i, cond := range conditions {
+		fld, err := s.checkField(cond.Field)
+		if err != nil {
+			return err
+		}
+		cond.Field = fld
+
+		if ref, ok := cond.Value.(*Field); ok {
+			fld, err = s.checkField(ref)
+			if err != nil {
+				return err
+			}
+			cond.Value = fld
+		}
+
+		if i == 0 {
+			cond.Connector = NAC
+		} else if cond.Connector == NAC {
+			cond.Connector = AND
+		}
+		check.Conditions = append(check.Conditions, cond)
+	}
+
+	idx := slices.IndexFunc(s.Checks, func(c *Check) bool { return c.Name == name })
+	if idx != -1 {
+		s.Checks[idx] = check
+		return nil
+	}
+
+	s.Checks = append(s.Checks, check)
+	return nil
+}
+
+/**
+* toFloat
+* @param value interface{}
+* @return float64, bool
+**/
+func toFloat(value interface{}) (float64, bool) {
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	}
+
+	return 0, false
+}
+
+/**
+* compare
+* @param a, b interface{}
+* @return int, bool // false when the values are not comparable
+**/
+func compare(a, b interface{}) (int, bool) {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok && x == y {
+			return 0, true
+		}
+	}
+
+	if fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b) {
+		return 0, true
+	}
+
+	return 0, false
+}
+
+/**
+* like
+* @param value interface{}, pattern interface{}, insensitive bool
+* @return bool
+**/
+func like(value, pattern interface{}, insensitive bool) bool {
+	expr := regexp.QuoteMeta(fmt.Sprintf("%v", pattern))
+	expr = strings.ReplaceAll(expr, "%", ".*")
+	expr = strings.ReplaceAll(expr, "_", ".")
+	if insensitive {
+		expr = "(?i)" + expr
+	}
+
+	result, err := regexp.MatchString("^"+expr+"$", fmt.Sprintf("%v", value))
+	return err == nil && result
+}
+
+/**
+* eval
+* @param cond *Condition, data et.Json
+* @return bool // operators without a Go equivalent are left to the database
+**/
+func eval(cond *Condition, data et.Json) bool {
+	value := data[cond.Field.As]
+	arg := cond.Value
+	if ref, ok := arg.(*Field); ok {
+		arg = data[ref.As]
+	}
+
+	switch cond.Operator {
+	case OpNull:
+		return value == nil
+	case OpNotNull:
+		return value != nil
+	case OpIn, OpNotIn:
+		in := slices.ContainsFunc(toList(arg), func(item interface{}) bool {
+			c, ok := compare(value, item)
+			return ok && c == 0
+		})
+		return in == (cond.Operator == OpIn)
+	case OpBetween, OpNotBetween:
+		var min, max interface{}
+		switch v := arg.(type) {
+		case BetweenValue:
+			min, max = v.Min, v.Max
+		case *BetweenValue:
+			min, max = v.Min, v.Max
+		case []interface{}:
+			if len(v) != 2 {
+				return true
+			}
+			min, max = v[0], v[1]
+		default:
+			return true
+		}
+		lo, ok1 := compare(value, min)
+		hi, ok2 := compare(value, max)
+		if !ok1 || !ok2 {
+			return true
+		}
+		return (lo >= 0 && hi <= 0) == (cond.Operator == OpBetween)
+	case OpLike:
+		return like(value, arg, false)
+	case OpILike:
+		return like(value, arg, true)
+	}
+
+	c, ok := compare(value, arg)
+	switch cond.Operator {
+	case OpEq:
+		return ok && c == 0
+	case OpNeg:
+		return !ok || c != 0
+	case OpLess:
+		return !ok || c < 0
+	case OpLessEq:
+		return !ok || c <= 0
+	case OpMore:
+		return !ok || c > 0
+	case OpMoreEq:
+		return !ok || c >= 0
+	}
+
+	return true
+}
+
+/**
+* Eval
+* @param data et.Json
+* @return bool // AND binds tighter than OR, as in SQL
+**/
+func (s *Check) Eval(data et.Json) bool {
+	result := false
+	group := true
+	for i, cond := range s.Conditions {
+		if i > 0 && cond.Connector == OR {
+			result = result || group
+			group = true
+		}
+		group = group && eval(cond, data)
+	}
+
+	return result || group
+}
+
+/**
+* validateChecks
+* @param data et.Json, result *ValidationError
+**/
+func (s *Model) validateChecks(data et.Json, result *ValidationError) {
+	for _, check := range s.Checks {
+		complete := true
+		for _, fld := range check.Fields() {
+			if _, ok := data[fld.As]; !ok {
+				complete = false
+				break
+			}
+		}
+
+		if !complete || check.Eval(data) {
+			continue
+		}
+
+		result.add(check.Name, fmt.Sprintf(MSG_VALIDATION_CHECK, check.Name), nil)
+	}
+}
+
+/**
+* ValidateChecks
+* @param data et.Json // the complete row, an update checks the current row merged with its changes
+* @return error
+**/
+func (s *Model) ValidateChecks(data et.Json) error {
+	result := &ValidationError{Model: s.Name}
+	s.validateChecks(data, result)
+	return result.result()
+}
@@ -1,6 +1,7 @@
 package jdb
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,6 +25,7 @@ type Cmd struct {
 	Data          []et.Json         `json:"data"`
 	New           et.Json           `json:"new"`
 	Returns       []*Field          `json:"returns"`
+	TenantId      string            `json:"tenant_id"`
 	IsDebug       bool              `json:"is_debug"`
 	beforeInserts []TriggerFunction `json:"-"`
 	beforeUpdates []TriggerFunction `json:"-"`
@@ -32,6 +34,7 @@ type Cmd struct {
 	afterUpdates  []TriggerFunction `json:"-"`
 	afterDeletes  []TriggerFunction `json:"-"`
 	tx            *Tx               `json:"-"`
+	ctx           context.Context   `json:"-"`
 	db            *DB               `json:"-"`
 }
 
@@ -203,14 +206,33 @@ func (s *Cmd) setTx(tx *Tx) *Cmd {
 	return s
 }
 
+/**
+* afterCommit
+* @param fn func() // runs when the Tx of the command commits, or now without a Tx
+**/
+func (s *Cmd) afterCommit(fn func()) {
+	if s.tx == nil {
+		fn()
+		return
+	}
+
+	s.tx.afterCommit(fn)
+}
+
 /**
 * insert
 * @return et.Items, error
 **/
 func (s *Cmd) insert() (et.Items, error) {
 	result := et.Items{}
-	for _, new := range s.Data {
+	for _, data := range s.Data {
 		old := et.Json{}
+		links := s.Model.takeLinks(data)
+		new, err := s.Model.validate(data, true)
+		if err != nil {
+			return et.Items{}, err
+		}
+
 		for _, fn := range s.beforeInserts {
 			err := fn(s.tx, old, new)
 			if err != nil {
@@ -222,22 +244,27 @@ func (s *Cmd) insert() (et.Items, error) {
 			continue
 		}
 
+		new, err = s.Model.validate(new, false)
+		if err != nil {
+			return et.Items{}, err
+		}
+
 		s.New = new
 		sql, err := s.db.Command(s)
 		if err != nil {
 			return et.Items{}, err
 		}
 
-		result, err = s.db.sqlTx(s.tx, sql)
+		items, err := s.db.queryTx(s.getContext(), s.tx, s.Model.Key(), string(s.Type), sql)
 		if err != nil {
 			return et.Items{}, err
 		}
 
-		if !result.Ok {
-			continue
+		if !items.Ok {
+			return et.Items{}, ErrNotInserted
 		}
 
-		new = result.First()
+		new = items.First()
 		for _, fn := range s.afterInserts {
 			err := fn(s.tx, old, new)
 			if err != nil {
@@ -245,6 +272,16 @@ func (s *Cmd) insert() (et.Items, error) {
 			}
 		}
 
+		err = s.Model.syncRollups(s.tx, old, new)
+		if err != nil {
+			return et.Items{}, err
+		}
+
+		err = s.Model.syncLinks(s.tx, new, links)
+		if err != nil {
+			return et.Items{}, err
+		}
+
 		result.Add(new)
 	}
 
@@ -258,15 +295,32 @@ func (s *Cmd) insert() (et.Items, error) {
 func (s *Cmd) update() (et.Items, error) {
 	from := s.Model
 	result := et.Items{}
-	for _, data := range s.Data {
-		current, err := NewQuery(from, "A").
+	wheres := s.Wheres
+	defer func() { s.Wheres = wheres }()
+	for _, item := range s.Data {
+		links := from.takeLinks(item)
+		data, err := from.validate(item, true)
+		if err != nil {
+			return et.Items{}, err
+		}
+
+		ql := NewQuery(from, from.Name).
+			Tenant(s.TenantId).
 			Current(data).
-			All()
+			Primary()
+		for _, cond := range wheres.Conditions {
+			ql.Wheres.add(cond)
+		}
+		current, err := ql.AllTx(s.tx)
 		if err != nil {
 			return et.Items{}, err
 		}
 
 		for _, old := range current.Result {
+			if len(from.PrimaryKeys) > 0 {
+				s.Wheres = newWhere().ByPk(from, old)
+			}
+
 			new := old.Clone()
 			for k, v := range data {
 				new[k] = v
@@ -279,22 +333,27 @@ func (s *Cmd) update() (et.Items, error) {
 				}
 			}
 
+			err = from.ValidateChecks(new)
+			if err != nil {
+				return et.Items{}, err
+			}
+
 			s.New = new
 			sql, err := s.db.Command(s)
 			if err != nil {
 				return et.Items{}, err
 			}
 
-			result, err = s.db.sqlTx(s.tx, sql)
+			items, err := s.db.queryTx(s.getContext(), s.tx, s.Model.Key(), string(s.Type), sql)
 			if err != nil {
 				return et.Items{}, err
 			}
 
-			if !result.Ok {
+			if !items.Ok {
 				continue
 			}
 
-			new = result.First()
+			new = items.First()
 			for _, fn := range s.afterUpdates {
 				err := fn(s.tx, old, new)
 				if err != nil {
@@ -302,6 +361,16 @@ func (s *Cmd) update() (et.Items, error) {
 				}
 			}
 
+			err = from.syncRollups(s.tx, old, new)
+			if err != nil {
+				return et.Items{}, err
+			}
+
+			err = from.syncLinks(s.tx, new, links)
+			if err != nil {
+				return et.Items{}, err
+			}
+
 			result.Add(new)
 		}
 	}
@@ -316,10 +385,40 @@ func (s *Cmd) update() (et.Items, error) {
 func (s *Cmd) delete() (et.Items, error) {
 	from := s.Model
 	result := et.Items{}
+	if len(s.Data) == 0 {
+		sql, err := s.db.Command(s)
+		if err != nil {
+			return et.Items{}, err
+		}
+
+		result, err = s.db.queryTx(s.getContext(), s.tx, s.Model.Key(), string(s.Type), sql)
+		if err != nil {
+			return et.Items{}, err
+		}
+
+		for _, old := range result.Result {
+			for _, fn := range s.afterDeletes {
+				err := fn(s.tx, old, et.Json{})
+				if err != nil {
+					return et.Items{}, err
+				}
+			}
+
+			err = from.syncRollups(s.tx, old, et.Json{})
+			if err != nil {
+				return et.Items{}, err
+			}
+		}
+
+		return result, nil
+	}
+
 	for _, data := range s.Data {
 		current, err := NewQuery(from, "A").
+			Tenant(s.TenantId).
 			Current(data).
-			All()
+			Primary().
+			AllTx(s.tx)
 		if err != nil {
 			return et.Items{}, err
 		}
@@ -338,7 +437,7 @@ func (s *Cmd) delete() (et.Items, error) {
 				return et.Items{}, err
 			}
 
-			result, err = s.db.sqlTx(s.tx, sql)
+			result, err = s.db.queryTx(s.getContext(), s.tx, s.Model.Key(), string(s.Type), sql)
 			if err != nil {
 				return et.Items{}, err
 			}
@@ -355,6 +454,11 @@ func (s *Cmd) delete() (et.Items, error) {
 				}
 			}
 
+			err = from.syncRollups(s.tx, old, new)
+			if err != nil {
+				return et.Items{}, err
+			}
+
 			result.Add(old)
 		}
 	}
@@ -374,20 +478,35 @@ func (s *Cmd) upsert() (et.Items, error) {
 	data := s.Data[0]
 	model := s.Model
 	exists, err := NewQuery(model, "").
+		Tenant(s.TenantId).
 		Current(data).
-		Exists()
+		Primary().
+		ExistsTx(s.tx)
 	if err != nil {
 		return et.Items{}, err
 	}
 
+	var result et.Items
 	if exists {
 		s.Type = UPDATE
 		s.Wheres.ByPk(model, data)
-		return s.update()
+		result, err = s.update()
+	} else {
+		s.Type = INSERT
+		result, err = s.insert()
+	}
+	if errors.Is(err, ErrNotInserted) {
+		return et.Items{}, ErrNotUpserted
+	}
+	if err != nil {
+		return et.Items{}, err
+	}
+
+	if !result.Ok {
+		return et.Items{}, ErrNotUpserted
 	}
 
-	s.Type = INSERT
-	return s.insert()
+	return result, nil
 }
 
 /**
@@ -447,18 +566,32 @@ func (s *Cmd) ExecTx(tx *Tx) (et.Items, error) {
 	}
 
 	s.setTx(tx)
+	err := s.scopeTenant()
+	if err != nil {
+		return et.Items{}, err
+	}
+
+	var result et.Items
 	switch s.Type {
 	case INSERT:
-		return s.insert()
+		result, err = s.insert()
 	case UPDATE:
-		return s.update()
+		result, err = s.update()
 	case DELETE:
-		return s.delete()
+		result, err = s.delete()
 	case UPSERT:
-		return s.upsert()
+		result, err = s.upsert()
 	default:
 		return et.Items{}, fmt.Errorf("invalid command: %s", s.Type)
 	}
+	key := s.Model.Key()
+	s.afterCommit(s.db.markWrite)
+	s.afterCommit(func() { invalidate(key) })
+	if err != nil {
+		return et.Items{}, err
+	}
+
+	return result, nil
 }
 
 /**
@@ -10,6 +10,8 @@ const (
 	PROJECT_ID string = "project_id"
 	CREATED_AT string = "created_at"
 	UPDATED_AT string = "updated_at"
+	SEARCH     string = "search"
+	CHILDREN   string = "children"
 )
 
 type TypeColumn string
@@ -26,6 +28,7 @@ const (
 	RELATION TypeColumn = "relation"
 	CALC     TypeColumn = "calc"
 	AGG      TypeColumn = "agg"
+	SUBQUERY TypeColumn = "subquery"
 )
 
 type TypeData string
@@ -46,8 +49,18 @@ const (
 	DATETIME TypeData = "datetime"
 	BOOLEAN  TypeData = "boolean"
 	GEOMETRY TypeData = "geometry"
+	DECIMAL  TypeData = "decimal"
+	UUID     TypeData = "uuid"
+	DATE     TypeData = "date"
+	TIME     TypeData = "time"
+	INTERVAL TypeData = "interval"
+	ARRAY    TypeData = "array"
+	ENUM     TypeData = "enum"
+	TSVECTOR TypeData = "tsvector"
 )
 
+const SRID int = 4326
+
 const (
 	ACTIVE     string = "active"
 	ARCHIVED   string = "archived"
@@ -65,9 +78,67 @@ type Column struct {
 	TypeData   TypeData    `json:"type_data"`
 	Default    interface{} `json:"default"`
 	Definition []byte      `json:"definition"`
+	Length     int         `json:"length"`
+	Precision  int         `json:"precision"`
+	Scale      int         `json:"scale"`
+	Elem       TypeData    `json:"elem"`
+	Values     []string    `json:"values"`
+	Srid       int         `json:"srid"`
 	model      *Model      `json:"-"`
 }
 
+/**
+* SetLength
+* @param length int
+* @return *Column
+**/
+func (s *Column) SetLength(length int) *Column {
+	s.Length = length
+	return s
+}
+
+/**
+* SetPrecision
+* @param precision, scale int
+* @return *Column
+**/
+func (s *Column) SetPrecision(precision, scale int) *Column {
+	s.Precision = precision
+	s.Scale = scale
+	return s
+}
+
+/**
+* SetElem
+* @param tpData TypeData
+* @return *Column
+**/
+func (s *Column) SetElem(tpData TypeData) *Column {
+	s.Elem = tpData
+	return s
+}
+
+/**
+* SetSrid
+* @param srid int
+* @return *Column
+**/
+func (s *Column) SetSrid(srid int) *Column {
+	s.Srid = srid
+	return s
+}
+
+/**
+* Enum
+* @param values ...string
+* @return *Column
+**/
+func (s *Column) Enum(values ...string) *Column {
+	s.TypeData = ENUM
+	s.Values = values
+	return s
+}
+
 /**
 * Field
 * @return Field
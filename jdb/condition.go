@@ -23,6 +23,14 @@ const (
 	OpNotNull    Operator = "not_null"
 	OpBetween    Operator = "between"
 	OpNotBetween Operator = "not_between"
+	OpWithin     Operator = "within"
+	OpIntersects Operator = "intersects"
+	OpDWithin    Operator = "dwithin"
+	OpSearch     Operator = "search"
+	OpILike      Operator = "ilike"
+	OpSimilar    Operator = "similar"
+	OpExists     Operator = "exists"
+	OpNotExists  Operator = "not_exists"
 )
 
 func (s Operator) Str() string {
@@ -46,6 +54,14 @@ func ToOperator(s string) Operator {
 		"not_null":    OpNotNull,
 		"between":     OpBetween,
 		"not_between": OpNotBetween,
+		"within":      OpWithin,
+		"intersects":  OpIntersects,
+		"dwithin":     OpDWithin,
+		"search":      OpSearch,
+		"ilike":       OpILike,
+		"similar":     OpSimilar,
+		"exists":      OpExists,
+		"not_exists":  OpNotExists,
 	}
 
 	result, ok := values[s]
@@ -73,6 +89,11 @@ type BetweenValue struct {
 	Max any
 }
 
+type GeoValue struct {
+	Geometry any     `json:"geometry"`
+	Distance float64 `json:"distance"`
+}
+
 type Condition struct {
 	Field     *Field    `json:"field"`
 	Operator  Operator  `json:"operator"`
@@ -85,10 +106,21 @@ type Condition struct {
 * @return et.Json
 **/
 func (s *Condition) ToJson() et.Json {
+	value := s.Value
+	if ql, ok := value.(*Ql); ok {
+		value = et.Json{"query": ql.queryJson()}
+	}
+
+	if s.Operator == OpExists || s.Operator == OpNotExists {
+		return et.Json{
+			s.Operator.Str(): value,
+		}
+	}
+
 	if s.Connector == NAC {
 		return et.Json{
 			s.Field.Name(): et.Json{
-				s.Operator.Str(): s.Value,
+				s.Operator.Str(): value,
 			},
 		}
 	}
@@ -96,7 +128,7 @@ func (s *Condition) ToJson() et.Json {
 	return et.Json{
 		s.Connector.Str(): et.Json{
 			s.Field.Name(): et.Json{
-				s.Operator.Str(): s.Value,
+				s.Operator.Str(): value,
 			},
 		},
 	}
@@ -104,15 +136,24 @@ func (s *Condition) ToJson() et.Json {
 
 /**
 * ToCondition
-* @param json et.Json
+* @param json et.Json // {"ref": "A.field"} values point at an outer query, {"query": {...}} values are subqueries
 * @return *Condition
 **/
 func ToCondition(json et.Json) *Condition {
 	getWhere := func(json et.Json) *Condition {
 		for fld := range json {
 			cond := json.Json(fld)
+			if op := ToOperator(fld); op == OpExists || op == OpNotExists {
+				return condition(&Field{}, jsonQuery(cond.Json("query")), op)
+			}
+
 			for cnd := range cond {
 				val := cond[cnd]
+				if ref := cond.Json(cnd).Str("ref"); ref != "" {
+					val = Ref(ref)
+				} else if query := cond.Json(cnd).Json("query"); len(query) > 0 {
+					val = jsonQuery(query)
+				}
 				return condition(fld, val, ToOperator(cnd))
 			}
 		}
@@ -178,11 +219,11 @@ func condition(field interface{}, value interface{}, op Operator) *Condition {
 		}
 	case *Agg:
 		result = &Condition{
-			Field: &Field{Field: v.Field, As: v.Agg},
+			Field: &Field{TypeColumn: AGG, Field: v, As: v.AsName()},
 		}
 	case Agg:
 		result = &Condition{
-			Field: &Field{Field: v.Field, As: v.Agg},
+			Field: &Field{TypeColumn: AGG, Field: &v, As: v.AsName()},
 		}
 	case string:
 		lst := strs.Split(v, ":")
@@ -272,21 +313,39 @@ func Like(field interface{}, value interface{}) *Condition {
 	return condition(field, value, OpLike)
 }
 
+/**
+* ILike
+* @param field interface{}, value interface{}
+* @return Condition
+**/
+func ILike(field interface{}, value interface{}) *Condition {
+	return condition(field, value, OpILike)
+}
+
+/**
+* Similar
+* @param field interface{}, value interface{}
+* @return Condition
+**/
+func Similar(field interface{}, value interface{}) *Condition {
+	return condition(field, value, OpSimilar)
+}
+
 /**
 * In
-* @param field interface{}, value []interface{}
+* @param field interface{}, value interface{} // []interface{} or *Ql
 * @return Condition
 **/
-func In(field interface{}, value []interface{}) *Condition {
+func In(field interface{}, value interface{}) *Condition {
 	return condition(field, value, OpIn)
 }
 
 /**
 * NotIn
-* @param field interface{}, value []interface{}
+* @param field interface{}, value interface{} // []interface{} or *Ql
 * @return Condition
 **/
-func NotIn(field interface{}, value []interface{}) *Condition {
+func NotIn(field interface{}, value interface{}) *Condition {
 	return condition(field, value, OpNotIn)
 }
 
@@ -343,3 +402,67 @@ func Between(field interface{}, min, max any) *Condition {
 func NotBetween(field interface{}, min, max any) *Condition {
 	return condition(field, BetweenValue{Min: min, Max: max}, OpNotBetween)
 }
+
+/**
+* Within
+* @param field interface{}, geometry any // GeoJSON
+* @return Condition
+**/
+func Within(field interface{}, geometry any) *Condition {
+	return condition(field, GeoValue{Geometry: geometry}, OpWithin)
+}
+
+/**
+* Intersects
+* @param field interface{}, geometry any // GeoJSON
+* @return Condition
+**/
+func Intersects(field interface{}, geometry any) *Condition {
+	return condition(field, GeoValue{Geometry: geometry}, OpIntersects)
+}
+
+/**
+* DWithin
+* @param field interface{}, geometry any, distance float64 // GeoJSON, meters
+* @return Condition
+**/
+func DWithin(field interface{}, geometry any, distance float64) *Condition {
+	return condition(field, GeoValue{Geometry: geometry, Distance: distance}, OpDWithin)
+}
+
+/**
+* Exists
+* @param ql *Ql
+* @return Condition
+**/
+func Exists(ql *Ql) *Condition {
+	return condition(&Field{}, ql, OpExists)
+}
+
+/**
+* NotExists
+* @param ql *Ql
+* @return Condition
+**/
+func NotExists(ql *Ql) *Condition {
+	return condition(&Field{}, ql, OpNotExists)
+}
+
+/**
+* Ref
+* @param name string // as.field, a field of an outer query
+* @return *Field
+**/
+func Ref(name string) *Field {
+	lst := strs.Split(name, ".")
+	if len(lst) == 1 {
+		return &Field{TypeColumn: COLUMN, Field: name, As: name}
+	}
+
+	return &Field{
+		TypeColumn: COLUMN,
+		From:       &From{As: lst[0]},
+		Field:      lst[1],
+		As:         lst[1],
+	}
+}
@@ -12,6 +12,9 @@ func (s *DB) initCore() error {
 	if err := defineSeries(s); err != nil {
 		return err
 	}
+	if err := defineMigrations(s); err != nil {
+		return err
+	}
 
 	return nil
 }
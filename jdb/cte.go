@@ -0,0 +1,134 @@
+package jdb
+
+import (
+	"fmt"
+
+	"github.com/cgalvisleon/et/et"
+)
+
+type Cte struct {
+	Name      string `json:"name"`
+	Query     *Ql    `json:"query"`
+	Recursive *Ql    `json:"recursive"`
+}
+
+type Tree struct {
+	ParentKey string `json:"parent_key"`
+	ChildKey  string `json:"child_key"`
+}
+
+/**
+* CteModel
+* @param name string, ql *Ql // the columns of the cte are the selects of ql
+* @return *Model
+**/
+func CteModel(name string, ql *Ql) *Model {
+	return derivedModel(ql, name)
+}
+
+/**
+* With
+* @param name string, ql *Ql
+* @return *Ql
+**/
+func (s *Ql) With(name string, ql *Ql) *Ql {
+	s.Ctes = append(s.Ctes, &Cte{
+		Name:  name,
+		Query: ql,
+	})
+	return s
+}
+
+/**
+* WithRecursive
+* @param name string, anchor, recursive *Ql
+* @return *Ql
+**/
+func (s *Ql) WithRecursive(name string, anchor, recursive *Ql) *Ql {
+	s.Ctes = append(s.Ctes, &Cte{
+		Name:      name,
+		Query:     anchor,
+		Recursive: recursive,
+	})
+	return s
+}
+
+/**
+* With
+* @param name string, ql *Ql
+* @return *Ql // a query from the cte
+**/
+func With(name string, ql *Ql) *Ql {
+	result := NewQuery(CteModel(name, ql), "A")
+	return result.With(name, ql)
+}
+
+/**
+* WithRecursive
+* @param name string, anchor, recursive *Ql
+* @return *Ql // a query from the cte
+**/
+func WithRecursive(name string, anchor, recursive *Ql) *Ql {
+	result := NewQuery(CteModel(name, anchor), "A")
+	return result.WithRecursive(name, anchor, recursive)
+}
+
+/**
+* Tree
+* @param parentKey, childKey string, root *Condition // parent_id, id, nil for rows without parent
+* @return *Ql // the rows under root read with a recursive cte, nested in children, the rows must not form a cycle
+**/
+func (s *Model) Tree(parentKey, childKey string, root *Condition) *Ql {
+	if root == nil {
+		root = Null(parentKey)
+	}
+
+	name := fmt.Sprintf("%s_tree", s.Name)
+	anchor := NewQuery(s, "A").
+		Select("A."+childKey, "A."+parentKey).
+		Where(root)
+	anchor.Type = SELECT
+	cte := CteModel(name, anchor)
+	recursive := NewQuery(s, "B").
+		Join(cte, "T", map[string]string{"B." + parentKey: "T." + childKey}).
+		Select("B."+childKey, "B."+parentKey)
+	recursive.Type = SELECT
+	for _, join := range recursive.Joins {
+		join.Embed = false
+	}
+
+	result := NewQuery(s, "A").
+		WithRecursive(name, anchor, recursive)
+	result.Where(In("A."+childKey, NewQuery(cte, "T").Select("T."+childKey)))
+	result.Tree = &Tree{
+		ParentKey: parentKey,
+		ChildKey:  childKey,
+	}
+	return result
+}
+
+/**
+* nest
+* @param items et.Items
+* @return et.Items // the roots, every row holds its rows in children
+**/
+func (s *Tree) nest(items et.Items) et.Items {
+	keys := make(map[string]et.Json, len(items.Result))
+	for _, item := range items.Result {
+		item[CHILDREN] = []et.Json{}
+		keys[fmt.Sprintf("%v", item[s.ChildKey])] = item
+	}
+
+	result := et.Items{Result: make([]et.Json, 0)}
+	for _, item := range items.Result {
+		parent, ok := keys[fmt.Sprintf("%v", item[s.ParentKey])]
+		if !ok || item[s.ParentKey] == nil {
+			result.Add(item)
+			continue
+		}
+
+		parent[CHILDREN] = append(parent[CHILDREN].([]et.Json), item)
+	}
+
+	return result
+}
@@ -0,0 +1,272 @@
+package jdb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/cgalvisleon/et/et"
+)
+
+type Cursor struct {
+	Values []interface{} `json:"values"`
+	Before bool          `json:"before"`
+}
+
+type Page struct {
+	Result []et.Json `json:"result"`
+	Count  int       `json:"count"`
+	Next   string    `json:"next"`
+	Prev   string    `json:"prev"`
+	Total  int       `json:"total"`
+}
+
+/**
+* EncodeCursor
+* @param values []interface{}
+* @return string
+**/
+func EncodeCursor(values []interface{}) string {
+	bt, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bt)
+}
+
+/**
+* DecodeCursor
+* @param cursor string
+* @return []interface{}, error
+**/
+func DecodeCursor(cursor string) ([]interface{}, error) {
+	bt, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	result := []interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(bt))
+	decoder.UseNumber()
+	err = decoder.Decode(&result)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return result, nil
+}
+
+/**
+* KeysetOrders
+* @return []*Orders // order by fields followed by the primary keys of the main from
+**/
+func (s *Ql) KeysetOrders() []*Orders {
+	result := []*Orders{}
+	for _, order := range s.OrdersBy {
+		if order.Nearest != nil {
+			continue
+		}
+		result = append(result, order)
+	}
+
+	if len(s.Froms) == 0 {
+		return result
+	}
+
+	from := s.Froms[0]
+	for _, name := range from.model.PrimaryKeys {
+		exists := false
+		for _, order := range result {
+			if order.Field.From == from && order.Field.As == name {
+				exists = true
+				break
+			}
+		}
+		if exists {
+			continue
+		}
+
+		fld := from.findField(name)
+		if fld != nil {
+			result = append(result, &Orders{Field: fld, Asc: true})
+		}
+	}
+
+	return result
+}
+
+/**
+* KeysetValues
+* @return []*Orders, []interface{}, error // the cursor values checked against the type of its order column
+**/
+func (s *Ql) KeysetValues() ([]*Orders, []interface{}, error) {
+	orders := s.KeysetOrders()
+	if s.Cursor == nil || len(orders) != len(s.Cursor.Values) {
+		return nil, nil, ErrInvalidCursor
+	}
+
+	result := make([]interface{}, len(s.Cursor.Values))
+	for i, value := range s.Cursor.Values {
+		switch value.(type) {
+		case string, json.Number, bool, nil:
+		default:
+			return nil, nil, ErrInvalidCursor
+		}
+
+		column := orders[i].Field.Column()
+		if column != nil && value != nil {
+			_, ok := coerce(column, value)
+			if !ok {
+				return nil, nil, ErrInvalidCursor
+			}
+		}
+		result[i] = value
+	}
+
+	return orders, result, nil
+}
+
+/**
+* cursorOf
+* @param item et.Json
+* @return string
+**/
+func (s *Ql) cursorOf(item et.Json) string {
+	values := []interface{}{}
+	for _, order := range s.KeysetOrders() {
+		values = append(values, item[order.Field.As])
+	}
+
+	return EncodeCursor(values)
+}
+
+/**
+* setCursor
+* @param cursor string, before bool
+* @return *Ql
+**/
+func (s *Ql) setCursor(cursor string, before bool) *Ql {
+	if cursor == "" {
+		s.Cursor = &Cursor{Values: []interface{}{}, Before: before}
+		return s
+	}
+
+	values, err := DecodeCursor(cursor)
+	if err != nil {
+		s.err = err
+		return s
+	}
+
+	if len(values) != len(s.KeysetOrders()) {
+		s.err = ErrInvalidCursor
+		return s
+	}
+
+	s.Cursor = &Cursor{Values: values, Before: before}
+	s.Page = 0
+	return s
+}
+
+/**
+* After
+* @param cursor string
+* @return *Ql
+**/
+func (s *Ql) After(cursor string) *Ql {
+	return s.setCursor(cursor, false)
+}
+
+/**
+* Before
+* @param cursor string
+* @return *Ql
+**/
+func (s *Ql) Before(cursor string) *Ql {
+	return s.setCursor(cursor, true)
+}
+
+/**
+* PaginateTx
+* @param tx *Tx, rows int, total bool // the query is copied, the caller can reuse it
+* @return *Page, error
+**/
+func (s *Ql) PaginateTx(tx *Tx, rows int, total bool) (*Page, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	ql := *s
+	s = &ql
+	if s.Cursor == nil {
+		s.Cursor = &Cursor{Values: []interface{}{}}
+	}
+
+	result := &Page{Result: []et.Json{}}
+	if total {
+		counter := *s
+		counter.Type = COUNTED
+		counter.Cursor = nil
+		counter.Page = 0
+		counter.Rows = 0
+		count, err := counter.CountTx(tx)
+		if err != nil {
+			return nil, err
+		}
+		result.Total = count
+	}
+
+	if rows <= 0 || rows > s.MaxRows {
+		rows = s.MaxRows
+	}
+	s.Page = 0
+	s.Rows = rows + 1
+	s.MaxRows = rows + 1
+	items, err := s.AllTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	more := len(items.Result) > rows
+	if more && s.Cursor.Before {
+		items.Result = items.Result[1:]
+	} else if more {
+		items.Result = items.Result[:rows]
+	}
+
+	result.Result = items.Result
+	result.Count = len(items.Result)
+	if result.Count == 0 {
+		return result, nil
+	}
+
+	first := items.Result[0]
+	last := items.Result[result.Count-1]
+	started := len(s.Cursor.Values) > 0
+	if s.Cursor.Before {
+		if more {
+			result.Prev = s.cursorOf(first)
+		}
+		if started {
+			result.Next = s.cursorOf(last)
+		}
+	} else {
+		if started {
+			result.Prev = s.cursorOf(first)
+		}
+		if more {
+			result.Next = s.cursorOf(last)
+		}
+	}
+
+	return result, nil
+}
+
+/**
+* Paginate
+* @param rows int, total bool
+* @return *Page, error
+**/
+func (s *Ql) Paginate(rows int, total bool) (*Page, error) {
+	return s.PaginateTx(nil, rows, total)
+}
@@ -0,0 +1,55 @@
+package jdb
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []interface{}
+		want   []interface{}
+	}{
+		{"empty", []interface{}{}, []interface{}{}},
+		{"string", []interface{}{"abc"}, []interface{}{"abc"}},
+		{"quote", []interface{}{"x' OR 1=1 --"}, []interface{}{"x' OR 1=1 --"}},
+		{"number", []interface{}{10, 1.5}, []interface{}{json.Number("10"), json.Number("1.5")}},
+		{"mixed", []interface{}{"a", 1, true, nil}, []interface{}{"a", json.Number("1"), true, nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := EncodeCursor(tt.values)
+			got, err := DecodeCursor(cursor)
+			if err != nil {
+				t.Fatalf("DecodeCursor(%q): %v", cursor, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeCursor(%q) = %#v, want %#v", cursor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "***"},
+		{"not json", "bm90IGpzb24"},
+		{"not array", "eyJhIjoxfQ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.cursor)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
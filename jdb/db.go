@@ -1,6 +1,7 @@
 package jdb
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -9,6 +10,7 @@ import (
 	"github.com/cgalvisleon/et/et"
 	"github.com/cgalvisleon/et/logs"
 	"github.com/cgalvisleon/et/strs"
+	"github.com/cgalvisleon/et/timezone"
 	"github.com/cgalvisleon/et/utility"
 )
 
@@ -25,6 +27,8 @@ type DB struct {
 	UseCore bool               `json:"use_core"`
 	driver  Driver             `json:"-"`
 	db      *sql.DB            `json:"-"`
+	router  *router            `json:"-"`
+	hooks   []QueryHook        `json:"-"`
 	IsDebug bool               `json:"-"`
 }
 
@@ -86,12 +90,12 @@ func (s *DB) init() error {
 		return errors.New(MSG_DRIVER_NOT_FOUND)
 	}
 
-	db, err := s.driver.Connect(s)
+	s.initHooks()
+	err := s.open()
 	if err != nil {
 		return err
 	}
 
-	s.db = db
 	if s.UseCore {
 		s.initCore()
 	}
@@ -108,6 +112,21 @@ func (s *DB) init() error {
 	return nil
 }
 
+/**
+* open
+* @return error // connects the primary and the replicas
+**/
+func (s *DB) open() error {
+	db, err := s.driver.Connect(s)
+	if err != nil {
+		return err
+	}
+
+	s.db = db
+	s.initReplicas()
+	return nil
+}
+
 /**
 * NewModel
 * @param schema, name string, version int
@@ -131,6 +150,8 @@ func (s *DB) NewModel(schema, name string, version int) (*Model, error) {
 		Name:          name,
 		Columns:       make([]*Column, 0),
 		Indexes:       make([]string, 0),
+		IndexSpecs:    make([]*IndexSpec, 0),
+		Checks:        make([]*Check, 0),
 		PrimaryKeys:   make([]string, 0),
 		ForeignKeys:   make([]*Detail, 0),
 		Unique:        make([]string, 0),
@@ -147,6 +168,7 @@ func (s *DB) NewModel(schema, name string, version int) (*Model, error) {
 		afterUpdates:  make([]TriggerFunction, 0),
 		afterDeletes:  make([]TriggerFunction, 0),
 		calcs:         make(map[string]DataContext),
+		validators:    make(map[string][]ValidatorFunc),
 		db:            s,
 		IsDebug:       s.IsDebug,
 	}
@@ -184,15 +206,46 @@ func (s *DB) Debug() {
 *
  */
 func (s *DB) sqlTx(tx *Tx, query string, arg ...any) (et.Items, error) {
-	query = SQLParse(query, arg...)
+	return s.queryTx(context.Background(), tx, "", "sql", query, arg...)
+}
+
+/**
+* queryTx
+* @param ctx context.Context, tx *Tx, model, operation, sql string, arg ...any
+* @return et.Items, error // the statement is reported to the query hooks
+**/
+func (s *DB) queryTx(ctx context.Context, tx *Tx, model, operation, query string, arg ...any) (result et.Items, err error) {
+	if s.db == nil {
+		return et.Items{}, ErrNotConnected
+	}
+
+	start := timezone.Now()
+	defer func() {
+		s.emit(QueryEvent{
+			Database:  s.Name,
+			Model:     model,
+			Operation: operation,
+			SQL:       query,
+			Args:      arg,
+			StartedAt: start,
+			Duration:  timezone.Now().Sub(start),
+			Rows:      result.Count,
+			Error:     err,
+			TxId:      txId(tx),
+			Ctx:       ctx,
+		})
+	}()
+
+	sql := SQLParse(query, arg...)
 	if tx != nil {
 		err := tx.Begin(s.db)
 		if err != nil {
 			return et.Items{}, err
 		}
 
-		rows, err := tx.Tx.Query(query)
+		rows, err := tx.Tx.QueryContext(ctx, sql)
 		if err != nil {
+			err = s.mapError(err)
 			errR := tx.Rollback()
 			if errR != nil {
 				err = fmt.Errorf(MSG_ROLLBACK_ERROR, errR)
@@ -203,15 +256,28 @@ func (s *DB) sqlTx(tx *Tx, query string, arg ...any) (et.Items, error) {
 		return result, nil
 	}
 
-	rows, err := s.db.Query(query)
+	rows, err := s.db.QueryContext(ctx, sql)
 	if err != nil {
-		return et.Items{}, err
+		return et.Items{}, s.mapError(err)
 	}
 
-	result := RowsToItems(rows)
+	result = RowsToItems(rows)
 	return result, nil
 }
 
+/**
+* mapError
+* @param err error
+* @return error
+**/
+func (s *DB) mapError(err error) error {
+	if err == nil || s.driver == nil {
+		return err
+	}
+
+	return s.driver.MapError(err)
+}
+
 /**
 * getSchema
 * @param name string
@@ -259,6 +325,7 @@ func (s *DB) GetModel(name string) (*Model, error) {
 	result.afterUpdates = make([]TriggerFunction, 0)
 	result.afterDeletes = make([]TriggerFunction, 0)
 	result.calcs = make(map[string]DataContext)
+	result.validators = make(map[string][]ValidatorFunc)
 	result.db = s
 
 	err = result.Init()
@@ -309,7 +376,7 @@ func (s *DB) loadModel(model *Model) error {
 		return err
 	}
 
-	_, err = s.sqlTx(nil, sql)
+	_, err = s.queryTx(context.Background(), nil, model.Key(), "load", sql)
 	if err != nil {
 		return err
 	}
@@ -317,6 +384,29 @@ func (s *DB) loadModel(model *Model) error {
 	return nil
 }
 
+/**
+* mutateModel
+* @param model *Model
+* @return error
+**/
+func (s *DB) mutateModel(model *Model) error {
+	if s.driver == nil {
+		return errors.New(MSG_DRIVER_NOT_FOUND)
+	}
+
+	sql, err := s.driver.Mutate(model)
+	if err != nil {
+		return err
+	}
+
+	if sql == "" {
+		return nil
+	}
+
+	_, err = s.queryTx(context.Background(), nil, model.Key(), "mutate", sql)
+	return err
+}
+
 /**
 * Command
 * @param command *Command
@@ -344,6 +434,11 @@ func (s *DB) Ql(ql *Ql) (string, error) {
 		return "", errors.New(MSG_DRIVER_NOT_FOUND)
 	}
 
+	err := ql.scopeTenant(ql.tenant())
+	if err != nil {
+		return "", err
+	}
+
 	if ql.IsDebug {
 		logs.Debugf("query:%s", ql.ToJson().ToEscapeHTML())
 	}
@@ -357,7 +452,27 @@ func (s *DB) Ql(ql *Ql) (string, error) {
 * @return et.Items, error
 **/
 func (s *DB) Query(query et.Json) (et.Items, error) {
-	return et.Items{}, nil
+	return s.QueryContext(context.Background(), query)
+}
+
+/**
+* QueryContext
+* @param ctx context.Context, query et.Json // {"from": "schema.name", "as": "A", "select": [...], "where": [...], "page": 1, "rows": 30}, the tenant comes from ctx
+* @return et.Items, error
+**/
+func (s *DB) QueryContext(ctx context.Context, query et.Json) (et.Items, error) {
+	ql, err := s.queryFrom(query, nil)
+	if err != nil {
+		return et.Items{}, err
+	}
+
+	ql.WithContext(ctx)
+	rows := query.Int("rows")
+	if rows <= 0 {
+		return ql.All()
+	}
+
+	return ql.Limit(query.Int("page"), rows)
 }
 
 /**
@@ -412,3 +527,136 @@ func (s *DB) Upsert(sql et.Json) (et.Items, error) {
 func (s *DB) From(sql et.Json) (et.Items, error) {
 	return et.Items{}, nil
 }
+
+/**
+* closePool
+* @return error // closes the connections of the primary and the replicas
+**/
+func (s *DB) closePool() error {
+	err := s.closeReplicas()
+	if err != nil {
+		return err
+	}
+
+	if s.db == nil {
+		return nil
+	}
+
+	err = s.db.Close()
+	if err != nil {
+		return err
+	}
+
+	s.db = nil
+	return nil
+}
+
+/**
+* release
+* @param fn func() error // runs with the connections closed, the database and its models are forgotten when it succeeds
+* @return error // on failure the database is connected again and keeps its catalog
+**/
+func (s *DB) release(fn func() error) error {
+	err := s.closePool()
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	if err != nil {
+		return errors.Join(err, s.open())
+	}
+
+	for _, schema := range s.Schemas {
+		for _, model := range schema.Models {
+			err := s.DeleteModel(model.Schema, model.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return DeleteDb(s.Name)
+}
+
+/**
+* Drop
+* @return error
+**/
+func (s *DB) Drop() error {
+	if s.driver == nil {
+		return errors.New(MSG_DRIVER_NOT_FOUND)
+	}
+
+	return s.release(func() error {
+		return s.driver.Drop(s)
+	})
+}
+
+/**
+* Archive
+* @param name string // the database is renamed to name
+* @return error
+**/
+func (s *DB) Archive(name string) error {
+	if s.driver == nil {
+		return errors.New(MSG_DRIVER_NOT_FOUND)
+	}
+
+	if !utility.ValidStr(name, 0, []string{}) {
+		return errors.New(MSG_NAME_REQUIRED)
+	}
+
+	return s.release(func() error {
+		return s.driver.Rename(s, utility.Normalize(name))
+	})
+}
+
+/**
+* Health
+* @param ctx context.Context
+* @return et.Json, error // status, latency in milliseconds and pool stats
+**/
+func (s *DB) Health(ctx context.Context) (et.Json, error) {
+	result := et.Json{
+		"database": s.Name,
+		"status":   "down",
+	}
+	if s.db == nil {
+		return result, ErrNotConnected
+	}
+
+	start := timezone.Now()
+	err := s.db.PingContext(ctx)
+	latency := timezone.Now().Sub(start).Milliseconds()
+	stats := s.db.Stats()
+	result["latency"] = latency
+	result["replicas"] = s.replicasHealth(ctx)
+	result["pool"] = et.Json{
+		"max_open_connections": stats.MaxOpenConnections,
+		"open_connections":     stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+		"wait_count":           stats.WaitCount,
+		"wait_duration":        stats.WaitDuration.Milliseconds(),
+		"max_idle_closed":      stats.MaxIdleClosed,
+		"max_idle_time_closed": stats.MaxIdleTimeClosed,
+		"max_lifetime_closed":  stats.MaxLifetimeClosed,
+	}
+	if err != nil {
+		result["error"] = err.Error()
+		return result, s.mapError(err)
+	}
+
+	result["status"] = "up"
+	return result, nil
+}
+
+/**
+* Close
+* @return error // closes the pool, the database stays in the catalog
+**/
+func (s *DB) Close() error {
+	forgetDb(s.Name)
+	return s.closePool()
+}
@@ -126,21 +126,21 @@ func (s *Model) DefineColumn(name string, tpData TypeData, defaultValue interfac
 }
 
 /**
-* DefineTable
-* @param name string
+* DefineForeignKey
+* @param to *Model, keys map[string]string, onDeleteCascade, onUpdateCascade bool // keys local:remote
 * @return error
 **/
 func (s *Model) DefineForeignKey(to *Model, keys map[string]string, onDeleteCascade, onUpdateCascade bool) error {
 	detail := newDetail(to, keys, []interface{}{}, onDeleteCascade, onUpdateCascade)
 	for fk, pk := range keys {
-		fld := s.FindField(pk)
+		fld := s.FindField(fk)
 		if fld == nil {
-			return fmt.Errorf(MSG_FIELD_NOT_FOUND, pk)
+			return fmt.Errorf(MSG_FIELD_NOT_FOUND, fk)
 		}
 
-		fld = to.FindField(fk)
+		fld = to.FindField(pk)
 		if fld == nil {
-			return fmt.Errorf(MSG_FIELD_NOT_FOUND, fk)
+			return fmt.Errorf(MSG_FIELD_NOT_FOUND, pk)
 		}
 	}
 	s.ForeignKeys = append(s.ForeignKeys, detail)
@@ -233,7 +233,7 @@ func (s *Model) DefineDetail(name string, keys map[string]string, version int) (
 
 /**
 * DefineRollup
-* @param name string, from string, keys map[string]string, selects []interface{}
+* @param name string, from string, keys map[string]string, selects []interface{} // a single aggregate, SUM("amount"), rolls up as a subquery
 * @return *Model
 **/
 func (s *Model) DefineRollup(name string, from *Model, keys map[string]string, selects []interface{}) error {
@@ -293,10 +293,17 @@ func (s *Model) DefineUpdatedAtField() *Model {
 
 /**
 * DefineStatusField
+* @param values ...string // optional, restricts the status to the values, StatusValues
 * @return *Model
 **/
-func (s *Model) DefineStatusField() *Model {
-	s.DefineColumn(STATUS, KEY, "")
+func (s *Model) DefineStatusField(values ...string) *Model {
+	column, err := s.DefineColumn(STATUS, KEY, "")
+	if err != nil || len(values) == 0 {
+		return s
+	}
+
+	column.Enum(values...)
+	column.Default = fmt.Sprintf("%v", Quoted(values[0]))
 	return s
 }
 
@@ -354,16 +361,25 @@ func (s *Model) DefineModel() *Model {
 }
 
 /**
-* DefineTenantModel
+* DefineProjectModel
 * @return *Model
 **/
 func (s *Model) DefineProjectModel() *Model {
+	return s.defineScopedModel(PROJECT_ID)
+}
+
+/**
+* defineScopedModel
+* @param key string // the indexed column that groups the rows, project_id or tenant_id
+* @return *Model
+**/
+func (s *Model) defineScopedModel(key string) *Model {
 	s.DefineCreatedAtField()
 	s.DefineUpdatedAtField()
 	s.DefineStatusField()
 	s.DefinePrimaryKeyField()
-	s.DefineColumn(PROJECT_ID, KEY, "")
-	s.Indexes = utility.Add(s.Indexes, PROJECT_ID)
+	s.DefineColumn(key, KEY, "")
+	s.Indexes = utility.Add(s.Indexes, key)
 	s.BeforeInsert(func(tx *Tx, old, new et.Json) error {
 		new.Set(CREATED_AT, timezone.Now())
 		new.Set(UPDATED_AT, timezone.Now())
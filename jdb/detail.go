@@ -8,6 +8,9 @@ type Detail struct {
 	OnUpdateCascade bool              `json:"on_update_cascade"`
 	Page            int               `json:"page"`
 	Rows            int               `json:"rows"`
+	Depth           int               `json:"depth"`
+	Through         *From             `json:"through"`
+	ThroughKeys     map[string]string `json:"through_keys"`
 }
 
 /**
@@ -24,6 +27,9 @@ func (s *Detail) setLimit(page, rows int) *Detail {
 		OnUpdateCascade: s.OnUpdateCascade,
 		Page:            page,
 		Rows:            rows,
+		Depth:           s.Depth,
+		Through:         s.Through,
+		ThroughKeys:     s.ThroughKeys,
 	}
 }
 
@@ -52,9 +58,11 @@ const (
 )
 
 type Joins struct {
-	Type TypeJoin
-	To   *From
-	Keys map[string]string
+	Type  TypeJoin
+	To    *From
+	Keys  map[string]string
+	On    []*Condition
+	Embed bool
 }
 
 /**
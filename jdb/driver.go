@@ -1,6 +1,10 @@
 package jdb
 
-import "database/sql"
+import (
+	"database/sql"
+
+	"github.com/cgalvisleon/et/et"
+)
 
 const (
 	DriverPostgres = "postgres"
@@ -9,10 +13,18 @@ const (
 
 type Driver interface {
 	Connect(db *DB) (*sql.DB, error)
+	Replica(db *DB, params et.Json) (*sql.DB, error)
 	Load(model *Model) (string, error)
 	Mutate(model *Model) (string, error)
 	Query(query *Ql) (string, error)
 	Command(command *Cmd) (string, error)
+	Lock(key int64) (string, error)
+	Cursor(name, query string, rows int) (string, string, string)
+	MapError(err error) error
+	Drop(db *DB) error
+	Rename(db *DB, name string) error
+	DropSchema(name string) (string, error)
+	RenameSchema(name, newName string) (string, error)
 }
 
 type DriverFn func() Driver
@@ -0,0 +1,74 @@
+package jdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cgalvisleon/et/strs"
+)
+
+type DbError struct {
+	Kind       error  `json:"-"`
+	Code       string `json:"code"`
+	Model      string `json:"model"`
+	Constraint string `json:"constraint"`
+	Field      string `json:"field"`
+	Message    string `json:"message"`
+	Err        error  `json:"-"`
+}
+
+/**
+* NewDbError
+* @param kind error, code string, err error
+* @return *DbError
+**/
+func NewDbError(kind error, code string, err error) *DbError {
+	result := &DbError{
+		Kind: kind,
+		Code: code,
+		Err:  err,
+	}
+	if err != nil {
+		result.Message = err.Error()
+	}
+
+	return result
+}
+
+/**
+* Error
+* @return string
+**/
+func (s *DbError) Error() string {
+	result := s.Kind.Error()
+	result = strs.Append(result, s.Model, ", model:")
+	result = strs.Append(result, s.Constraint, ", constraint:")
+	result = strs.Append(result, s.Field, ", field:")
+	if s.Message != "" {
+		result = fmt.Sprintf("%s (%s)", result, s.Message)
+	}
+
+	return result
+}
+
+/**
+* Unwrap
+* @return []error
+**/
+func (s *DbError) Unwrap() []error {
+	result := []error{s.Kind}
+	if s.Err != nil {
+		result = append(result, s.Err)
+	}
+
+	return result
+}
+
+/**
+* IsRetryable
+* @param err error
+* @return bool
+**/
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrSerialization) || errors.Is(err, ErrDeadlock)
+}
@@ -12,6 +12,7 @@ type From struct {
 	Name     string `json:"name"`
 	Table    string `json:"table"`
 	As       string `json:"as"`
+	Query    *Ql    `json:"query"`
 	model    *Model `json:"-"`
 }
 
@@ -38,6 +39,14 @@ func (s *From) findField(name string) *Field {
 	}
 }
 
+/**
+* Model
+* @return *Model
+**/
+func (s *From) Model() *Model {
+	return s.model
+}
+
 /**
 * Hidden
 * @return []string
@@ -46,9 +55,21 @@ func (s *From) Hidden() []string {
 	return s.model.Hidden
 }
 
+type Window struct {
+	Partition  []string  `json:"partition"`
+	Order      []string  `json:"order"`
+	Partitions []*Field  `json:"-"`
+	Orders     []*Orders `json:"-"`
+}
+
 type Agg struct {
-	Agg   string `json:"agg"`
-	Field string `json:"field"`
+	Agg      string        `json:"agg"`
+	Field    string        `json:"field"`
+	Alias    string        `json:"as"`
+	Distinct bool          `json:"distinct"`
+	Args     []interface{} `json:"args"`
+	Window   *Window       `json:"window"`
+	Target   *Field        `json:"-"`
 }
 
 /**
@@ -56,10 +77,45 @@ type Agg struct {
 * @return string
 **/
 func (s *Agg) Name() string {
-	return fmt.Sprintf(`%s(%s):%s`, s.Agg, s.Field, s.Agg)
+	return fmt.Sprintf(`%s(%s):%s`, s.Agg, s.Field, s.AsName())
 }
 
-var Aggs = []string{"count", "sum", "avg", "max", "min", "exp"}
+/**
+* AsName
+* @return string
+**/
+func (s *Agg) AsName() string {
+	if s.Alias != "" {
+		return s.Alias
+	}
+
+	return s.Agg
+}
+
+/**
+* As
+* @param name string
+* @return *Agg
+**/
+func (s *Agg) As(name string) *Agg {
+	s.Alias = name
+	return s
+}
+
+/**
+* Over
+* @param partition, order []string // order fields accept a desc suffix, "created_at desc"
+* @return *Agg
+**/
+func (s *Agg) Over(partition, order []string) *Agg {
+	s.Window = &Window{
+		Partition: partition,
+		Order:     order,
+	}
+	return s
+}
+
+var Aggs = []string{"count", "sum", "avg", "max", "min", "string_agg", "array_agg", "jsonb_agg", "percentile_cont", "percentile_disc", "row_number", "rank", "dense_rank", "lag", "lead", "first_value", "last_value"}
 
 /**
 * agg
@@ -70,6 +126,7 @@ func agg(agg string, field string) *Agg {
 	return &Agg{
 		Agg:   agg,
 		Field: field,
+		Args:  []interface{}{},
 	}
 }
 
@@ -121,12 +178,127 @@ func MIN(field string) *Agg {
 /**
 * EXP
 * @param field string
-* @return *Agg
+* @return *Agg // Deprecated: exp is not an aggregate function, queries skip it
 **/
 func EXP(field string) *Agg {
 	return agg("exp", field)
 }
 
+/**
+* CountDistinct
+* @param field string
+* @return *Agg
+**/
+func CountDistinct(field string) *Agg {
+	result := agg("count", field)
+	result.Distinct = true
+	return result
+}
+
+/**
+* StringAgg
+* @param field, separator string
+* @return *Agg
+**/
+func StringAgg(field, separator string) *Agg {
+	result := agg("string_agg", field)
+	result.Args = append(result.Args, separator)
+	return result
+}
+
+/**
+* ArrayAgg
+* @param field string
+* @return *Agg
+**/
+func ArrayAgg(field string) *Agg {
+	return agg("array_agg", field)
+}
+
+/**
+* JsonAgg
+* @param field string
+* @return *Agg
+**/
+func JsonAgg(field string) *Agg {
+	return agg("jsonb_agg", field)
+}
+
+/**
+* Percentile
+* @param field string, fraction float64 // 0.5 is the median
+* @return *Agg
+**/
+func Percentile(field string, fraction float64) *Agg {
+	result := agg("percentile_cont", field)
+	result.Args = append(result.Args, fraction)
+	return result
+}
+
+/**
+* RowNumber
+* @return *Agg
+**/
+func RowNumber() *Agg {
+	return agg("row_number", "")
+}
+
+/**
+* Rank
+* @return *Agg
+**/
+func Rank() *Agg {
+	return agg("rank", "")
+}
+
+/**
+* DenseRank
+* @return *Agg
+**/
+func DenseRank() *Agg {
+	return agg("dense_rank", "")
+}
+
+/**
+* Lag
+* @param field string, offset int
+* @return *Agg
+**/
+func Lag(field string, offset int) *Agg {
+	result := agg("lag", field)
+	result.Args = append(result.Args, offset)
+	return result
+}
+
+/**
+* Lead
+* @param field string, offset int
+* @return *Agg
+**/
+func Lead(field string, offset int) *Agg {
+	result := agg("lead", field)
+	result.Args = append(result.Args, offset)
+	return result
+}
+
+/**
+* FirstValue
+* @param field string
+* @return *Agg
+**/
+func FirstValue(field string) *Agg {
+	return agg("first_value", field)
+}
+
+/**
+* LastValue
+* @param field string
+* @return *Agg
+**/
+func LastValue(field string) *Agg {
+	return agg("last_value", field)
+}
+
 type Field struct {
 	TypeColumn TypeColumn  `json:"type_column"`
 	From       *From       `json:"from"`
@@ -136,6 +308,23 @@ type Field struct {
 	Rows       int         `json:"rows"`
 }
 
+/**
+* Column
+* @return *Column
+**/
+func (s *Field) Column() *Column {
+	if s.From == nil || s.From.model == nil {
+		return nil
+	}
+
+	name, ok := s.Field.(string)
+	if !ok {
+		return nil
+	}
+
+	return s.From.model.FindColumn(name)
+}
+
 /**
 * Name
 * @return string
@@ -61,6 +61,15 @@ func SQLUnQuote(sql string, args ...any) string {
 	return sql
 }
 
+/**
+* escapeQuote
+* @param val string
+* @return string // single quotes doubled, safe inside a SQL string literal
+**/
+func escapeQuote(val string) string {
+	return strings.ReplaceAll(val, "'", "''")
+}
+
 /**
 * Quote
 * @param val any
@@ -70,7 +79,13 @@ func Quoted(val any) any {
 	format := `'%v'`
 	switch v := val.(type) {
 	case string:
-		return fmt.Sprintf(format, v)
+		return fmt.Sprintf(format, escapeQuote(v))
+	case json.Number:
+		return v.String()
+	case time.Duration:
+		return fmt.Sprintf(`'%f seconds'::INTERVAL`, v.Seconds())
+	case int8, uint, uint8, uint16, uint32, uint64:
+		return v
 	case int:
 		return v
 	case float64:
@@ -86,18 +101,18 @@ func Quoted(val any) any {
 	case bool:
 		return v
 	case time.Time:
-		return fmt.Sprintf(format, v.Format("2006-01-02 15:04:05"))
+		return fmt.Sprintf(format, v.Format("2006-01-02 15:04:05.999999"))
 	case et.Json:
-		return fmt.Sprintf(format, v.ToString())
+		return fmt.Sprintf(format, escapeQuote(v.ToString()))
 	case map[string]interface{}:
-		return fmt.Sprintf(format, et.Json(v).ToString())
-	case []string, []et.Json, []interface{}, []map[string]interface{}:
+		return fmt.Sprintf(format, escapeQuote(et.Json(v).ToString()))
+	case []string, []et.Json, []interface{}, []map[string]interface{}, []int, []int64, []float64, []bool:
 		bt, err := json.Marshal(v)
 		if err != nil {
 			logs.Errorf("Quote, type:%v, value:%v, error marshalling array: %v", reflect.TypeOf(v), v, err)
 			return strs.Format(format, `[]`)
 		}
-		return fmt.Sprintf(format, string(bt))
+		return fmt.Sprintf(format, escapeQuote(string(bt)))
 	case []uint8:
 		b := []byte(val.([]uint8))
 		return fmt.Sprintf("'\\x%s'", hex.EncodeToString(b))
@@ -134,7 +149,7 @@ func Literal(val any) any {
 	case bool:
 		return v
 	case time.Time:
-		return fmt.Sprintf(format, v.Format("2006-01-02 15:04:05"))
+		return fmt.Sprintf(format, v.Format("2006-01-02 15:04:05.999999"))
 	case et.Json:
 		return fmt.Sprintf(format, v.ToString())
 	case map[string]interface{}:
@@ -174,6 +189,160 @@ func insertBeforeLast[T any](s []T, v T) []T {
 	return s
 }
 
+/**
+* parseArray
+* @param literal, elemType string // {a,b,"c d",NULL}
+* @return []interface{}
+**/
+func parseArray(literal, elemType string) []interface{} {
+	result := []interface{}{}
+	literal = strings.TrimSpace(literal)
+	if len(literal) < 2 || literal[0] != '{' || literal[len(literal)-1] != '}' {
+		return result
+	}
+
+	body := literal[1 : len(literal)-1]
+	if body == "" {
+		return result
+	}
+
+	elems := []string{}
+	quoted := []bool{}
+	current := strings.Builder{}
+	inQuotes := false
+	wasQuoted := false
+	for i := 0; i < len(body); i++ {
+		ch := body[i]
+		switch {
+		case ch == '\\' && inQuotes && i+1 < len(body):
+			i++
+			current.WriteByte(body[i])
+		case ch == '"':
+			inQuotes = !inQuotes
+			wasQuoted = true
+		case ch == ',' && !inQuotes:
+			elems = append(elems, current.String())
+			quoted = append(quoted, wasQuoted)
+			current.Reset()
+			wasQuoted = false
+		default:
+			current.WriteByte(ch)
+		}
+	}
+	elems = append(elems, current.String())
+	quoted = append(quoted, wasQuoted)
+
+	for i, elem := range elems {
+		if elem == "NULL" && !quoted[i] {
+			result = append(result, nil)
+			continue
+		}
+
+		result = append(result, scanElem(elemType, elem))
+	}
+
+	return result
+}
+
+/**
+* scanElem
+* @param dbType, val string
+* @return interface{} // an element of an array
+**/
+func scanElem(dbType, val string) interface{} {
+	switch dbType {
+	case "INT2", "INT4", "INT8":
+		result, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return val
+		}
+		return result
+	case "NUMERIC":
+		return json.Number(val)
+	case "FLOAT4", "FLOAT8":
+		result, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return val
+		}
+		return result
+	case "BOOL":
+		return val == "t" || val == "true"
+	case "JSON", "JSONB":
+		var result interface{}
+		err := json.Unmarshal([]byte(val), &result)
+		if err != nil {
+			return val
+		}
+		return result
+	default:
+		return val
+	}
+}
+
+/**
+* scanValue
+* @param dbType string, val interface{}
+* @return interface{} // the bytes are read as json or as text, numerics as json.Number, arrays as slices
+**/
+func scanValue(dbType string, val interface{}) interface{} {
+	if v, ok := val.(time.Time); ok {
+		switch dbType {
+		case "DATE":
+			return v.Format("2006-01-02")
+		case "TIME", "TIMETZ":
+			return v.Format("15:04:05.999999")
+		default:
+			return v
+		}
+	}
+
+	v, ok := val.([]byte)
+	if !ok {
+		return val
+	}
+
+	if strings.HasPrefix(dbType, "_") {
+		return parseArray(string(v), dbType[1:])
+	}
+
+	if dbType == "NUMERIC" {
+		return json.Number(string(v))
+	}
+
+	var result interface{}
+	err := json.Unmarshal(v, &result)
+	if err != nil {
+		return string(v)
+	}
+
+	return result
+}
+
+/**
+* scanRow
+* @param rows *sql.Rows, types []*sql.ColumnType
+* @return et.Json, error
+**/
+func scanRow(rows *sql.Rows, types []*sql.ColumnType) (et.Json, error) {
+	values := make([]interface{}, len(types))
+	pointers := make([]interface{}, len(types))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	err := rows.Scan(pointers...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := et.Json{}
+	for i, tp := range types {
+		result[tp.Name()] = scanValue(tp.DatabaseTypeName(), values[i])
+	}
+
+	return result, nil
+}
+
 /**
 * RowsToItems
 * @param rows *sql.Rows
@@ -187,40 +356,58 @@ func RowsToItems(rows *sql.Rows) et.Items {
 		result.Add(item)
 	}
 
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		logs.Errorf("RowsToItems, error reading column types: %v", err)
+		return result
+	}
+
 	for rows.Next() {
-		var item et.Json
-		item.ScanRows(rows)
-
-		if len(item) == 1 {
-			for _, v := range item {
-				switch val := v.(type) {
-				case et.Json:
-					append(val)
-				case map[string]interface{}:
-					append(et.Json(val))
-				default:
-					append(item)
-				}
-			}
-		} else {
-			append(item)
+		item, err := scanRow(rows, types)
+		if err != nil {
+			logs.Errorf("RowsToItems, error scanning row: %v", err)
+			continue
 		}
+
+		append(unwrapRow(item))
 	}
 
 	return result
 }
 
+/**
+* unwrapRow
+* @param item et.Json
+* @return et.Json // the json object when the row has a single json column
+**/
+func unwrapRow(item et.Json) et.Json {
+	if len(item) != 1 {
+		return item
+	}
+
+	for _, v := range item {
+		switch val := v.(type) {
+		case et.Json:
+			return val
+		case map[string]interface{}:
+			return et.Json(val)
+		}
+	}
+
+	return item
+}
+
 /**
 * findField
 * @param froms []*From, name string // from.name:as|1:30
 * @return *Field
 **/
 func findField(froms []*From, name string) *Field {
-	pattern1 := regexp.MustCompile(`^([A-Za-z0-9]+)\.([A-Za-z0-9]+):([A-Za-z0-9]+)$`) // from.name:as
-	pattern2 := regexp.MustCompile(`^([A-Za-z0-9]+)\.([A-Za-z0-9]+)$`)                // from.name
-	pattern3 := regexp.MustCompile(`^([A-Za-z]+)\((.+)\):([A-Za-z0-9]+)$`)            // agg(field):as
-	pattern4 := regexp.MustCompile(`^([A-Za-z]+)\((.+)\)`)                            // agg(field)
-	pattern5 := regexp.MustCompile(`^(\d+)\|(\d+)$`)                                  // page:rows
+	pattern1 := regexp.MustCompile(`^([A-Za-z0-9_]+)\.([A-Za-z0-9_]+):([A-Za-z0-9_]+)$`) // from.name:as
+	pattern2 := regexp.MustCompile(`^([A-Za-z0-9_]+)\.([A-Za-z0-9_]+)$`)                 // from.name
+	pattern3 := regexp.MustCompile(`^([A-Za-z_]+)\((.*)\):([A-Za-z0-9_]+)$`)             // agg(field):as
+	pattern4 := regexp.MustCompile(`^([A-Za-z_]+)\((.*)\)$`)                             // agg(field)
+	pattern5 := regexp.MustCompile(`^(\d+)\|(\d+)$`)                                     // page:rows
 
 	split := strings.Split(name, "|")
 	if len(split) == 2 {
@@ -291,42 +478,12 @@ func findField(froms []*From, name string) *Field {
 	} else if pattern3.MatchString(name) {
 		matches := pattern3.FindStringSubmatch(name)
 		if len(matches) == 4 {
-			agg := matches[1]
-			name = matches[2]
-			as := matches[3]
-			if !slices.Contains(Aggs, agg) {
-				return nil
-			}
-			result := findField(froms, name)
-			if result != nil {
-				result.TypeColumn = AGG
-				result.Field = &Agg{
-					Agg:   agg,
-					Field: name,
-				}
-				result.As = as
-				return result
-			}
+			return aggField(froms, &Agg{Agg: matches[1], Field: matches[2], Alias: matches[3]})
 		}
 	} else if pattern4.MatchString(name) {
 		matches := pattern4.FindStringSubmatch(name)
 		if len(matches) == 3 {
-			agg := matches[1]
-			name = matches[2]
-			as := agg
-			if !slices.Contains(Aggs, agg) {
-				return nil
-			}
-			result := findField(froms, name)
-			if result != nil {
-				result.TypeColumn = AGG
-				result.Field = &Agg{
-					Agg:   agg,
-					Field: name,
-				}
-				result.As = as
-				return result
-			}
+			return aggField(froms, &Agg{Agg: matches[1], Field: matches[2]})
 		}
 	} else {
 		for _, f := range froms {
@@ -340,3 +497,79 @@ func findField(froms []*From, name string) *Field {
 
 	return nil
 }
+
+/**
+* aggField
+* @param froms []*From, agg *Agg
+* @return *Field
+**/
+func aggField(froms []*From, agg *Agg) *Field {
+	if len(froms) == 0 || !slices.Contains(Aggs, agg.Agg) {
+		return nil
+	}
+
+	from := froms[0]
+	result := *agg
+	if agg.Field != "" && agg.Field != "*" {
+		fld := findField(froms, agg.Field)
+		if fld == nil {
+			return nil
+		}
+		from = fld.From
+		result.Target = fld
+	}
+
+	if agg.Window != nil {
+		window := aggWindow(froms, agg.Window)
+		if window == nil {
+			return nil
+		}
+		result.Window = window
+	}
+
+	return &Field{
+		TypeColumn: AGG,
+		From:       from,
+		Field:      &result,
+		As:         agg.AsName(),
+	}
+}
+
+/**
+* aggWindow
+* @param froms []*From, window *Window
+* @return *Window // the partition and order fields found in froms, nil when a field is not found
+**/
+func aggWindow(froms []*From, window *Window) *Window {
+	result := &Window{
+		Partition:  window.Partition,
+		Order:      window.Order,
+		Partitions: make([]*Field, 0, len(window.Partition)),
+		Orders:     make([]*Orders, 0, len(window.Order)),
+	}
+	for _, name := range window.Partition {
+		fld := findField(froms, name)
+		if fld == nil {
+			return nil
+		}
+		result.Partitions = append(result.Partitions, fld)
+	}
+
+	for _, name := range window.Order {
+		def := strings.Fields(name)
+		if len(def) == 0 {
+			continue
+		}
+
+		fld := findField(froms, def[0])
+		if fld == nil {
+			return nil
+		}
+		result.Orders = append(result.Orders, &Orders{
+			Field: fld,
+			Asc:   len(def) == 1 || !strings.EqualFold(def[1], "desc"),
+		})
+	}
+
+	return result
+}
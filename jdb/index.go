@@ -0,0 +1,80 @@
+package jdb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/cgalvisleon/et/utility"
+)
+
+type TypeIndex string
+
+const (
+	BTREE TypeIndex = "btree"
+	GIN   TypeIndex = "gin"
+	GIST  TypeIndex = "gist"
+	BRIN  TypeIndex = "brin"
+	HASH  TypeIndex = "hash"
+)
+
+var TypeIndexes = []TypeIndex{BTREE, GIN, GIST, BRIN, HASH}
+
+type IndexSpec struct {
+	Name    string    `json:"name"`
+	Columns []string  `json:"columns"`
+	Unique  bool      `json:"unique"`
+	Method  TypeIndex `json:"method"`
+	Where   string    `json:"where"`
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+/**
+* IsColumn
+* @param column string
+* @return bool // false for expressions, lower(email) or source->>'sku'
+**/
+func (s *IndexSpec) IsColumn(column string) bool {
+	return identifier.MatchString(column)
+}
+
+/**
+* DefineIndexOn
+* @param name string, spec IndexSpec // composite, unique, partial, expression and method specific indexes
+* @return error
+**/
+func (s *Model) DefineIndexOn(name string, spec IndexSpec) error {
+	if !utility.ValidStr(name, 0, []string{}) {
+		return errors.New(MSG_NAME_REQUIRED)
+	}
+
+	if len(spec.Columns) == 0 {
+		return fmt.Errorf(MSG_ATTRIBUTE_REQUIRED, "columns")
+	}
+
+	if spec.Method == "" {
+		spec.Method = BTREE
+	}
+
+	if !slices.Contains(TypeIndexes, spec.Method) {
+		return fmt.Errorf(MSG_INDEX_METHOD_INVALID, spec.Method)
+	}
+
+	for _, column := range spec.Columns {
+		if spec.IsColumn(column) && s.idxColumn(column) == -1 {
+			return fmt.Errorf(MSG_FIELD_NOT_FOUND, column)
+		}
+	}
+
+	spec.Name = name
+	idx := slices.IndexFunc(s.IndexSpecs, func(index *IndexSpec) bool { return index.Name == name })
+	if idx != -1 {
+		s.IndexSpecs[idx] = &spec
+		return nil
+	}
+
+	s.IndexSpecs = append(s.IndexSpecs, &spec)
+	return nil
+}
@@ -2,6 +2,7 @@ package jdb
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/cgalvisleon/et/et"
 	"github.com/cgalvisleon/et/utility"
@@ -9,6 +10,7 @@ import (
 
 var (
 	dbs    map[string]*DB
+	dbsMu  sync.RWMutex
 	models map[string]*Model
 )
 
@@ -17,6 +19,41 @@ func init() {
 	models = make(map[string]*Model)
 }
 
+/**
+* loadDb
+* @param name string
+* @return *DB, bool
+**/
+func loadDb(name string) (*DB, bool) {
+	dbsMu.RLock()
+	defer dbsMu.RUnlock()
+
+	result, ok := dbs[name]
+	return result, ok
+}
+
+/**
+* storeDb
+* @param name string, db *DB
+**/
+func storeDb(name string, db *DB) {
+	dbsMu.Lock()
+	defer dbsMu.Unlock()
+
+	dbs[name] = db
+}
+
+/**
+* forgetDb
+* @param name string
+**/
+func forgetDb(name string) {
+	dbsMu.Lock()
+	defer dbsMu.Unlock()
+
+	delete(dbs, name)
+}
+
 /**
 * Connect
 * @param name string, params et.Json
@@ -28,7 +65,7 @@ func Connect(name string, params et.Json) (*DB, error) {
 	}
 
 	name = utility.Normalize(name)
-	result, ok := dbs[name]
+	result, ok := loadDb(name)
 	if ok {
 		return result, nil
 	}
@@ -51,7 +88,7 @@ func Connect(name string, params et.Json) (*DB, error) {
 		return nil, err
 	}
 
-	dbs[name] = result
+	storeDb(name, result)
 	return result, nil
 }
 
@@ -66,7 +103,7 @@ func GetDb(name string) (*DB, error) {
 	}
 
 	name = utility.Normalize(name)
-	result, ok := dbs[name]
+	result, ok := loadDb(name)
 	if ok {
 		return result, nil
 	}
@@ -82,7 +119,7 @@ func GetDb(name string) (*DB, error) {
 			return nil, err
 		}
 
-		dbs[name] = result
+		storeDb(name, result)
 		return result, nil
 	}
 
@@ -95,10 +132,7 @@ func GetDb(name string) (*DB, error) {
 * @return error
 **/
 func DeleteDb(name string) error {
-	_, ok := dbs[name]
-	if ok {
-		delete(dbs, name)
-	}
+	forgetDb(name)
 
 	err := deleteCatalog("db", name)
 	if err != nil {
@@ -0,0 +1,95 @@
+package jdb
+
+import "fmt"
+
+/**
+* relationKeys
+* @param from *From, model *Model // keys of from.model towards model
+* @return map[string]string // local:remote
+**/
+func relationKeys(from *From, model *Model) map[string]string {
+	details := append([]*Detail{}, from.model.ForeignKeys...)
+	for _, relation := range from.model.Relations {
+		details = append(details, relation)
+	}
+
+	for _, detail := range details {
+		if detail.To.Name != model.Name || detail.To.Schema != model.Schema {
+			continue
+		}
+
+		return detail.Keys
+	}
+
+	return nil
+}
+
+/**
+* joinKeys
+* @param model *Model
+* @return map[string]string
+**/
+func (s *Ql) joinKeys(model *Model) map[string]string {
+	result := map[string]string{}
+	for _, from := range s.Froms {
+		keys := relationKeys(from, model)
+		for local, remote := range keys {
+			result[from.As+"."+local] = remote
+		}
+		if len(result) > 0 {
+			return result
+		}
+
+		keys = relationKeys(model.from(), from.model)
+		for local, remote := range keys {
+			result[from.As+"."+remote] = local
+		}
+		if len(result) > 0 {
+			return result
+		}
+	}
+
+	return result
+}
+
+/**
+* joinOn
+* @param from *From, keys map[string]string // the key of the previous froms, the value of the joined from
+* @return []*Condition, error
+**/
+func (s *Ql) joinOn(from *From, keys map[string]string) ([]*Condition, error) {
+	froms := []*From{}
+	for _, f := range s.Froms {
+		if f != from {
+			froms = append(froms, f)
+		}
+	}
+	to := []*From{from}
+
+	result := []*Condition{}
+	for key, value := range keys {
+		left := findField(froms, key)
+		right := findField(to, value)
+		if left == nil || right == nil {
+			swapLeft := findField(froms, value)
+			swapRight := findField(to, key)
+			if swapLeft != nil && swapRight != nil {
+				left, right = swapLeft, swapRight
+			}
+		}
+
+		if left == nil {
+			return nil, fmt.Errorf(MSG_FIELD_NOT_FOUND, key)
+		}
+
+		if right == nil {
+			return nil, fmt.Errorf(MSG_FIELD_NOT_FOUND, value)
+		}
+
+		cond := Eq(right, left)
+		cond.Connector = AND
+		result = append(result, cond)
+	}
+
+	return result, nil
+}
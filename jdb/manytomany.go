@@ -0,0 +1,410 @@
+package jdb
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/cgalvisleon/et/et"
+)
+
+/**
+* DefineManyToMany
+* @param name string, other *Model, through string
+* @return *Model, error // the junction model
+**/
+func (s *Model) DefineManyToMany(name string, other *Model, through string) (*Model, error) {
+	if len(s.PrimaryKeys) == 0 {
+		return nil, fmt.Errorf(MSG_ATTRIBUTE_REQUIRED, "primary keys of "+s.Name)
+	}
+
+	if len(other.PrimaryKeys) == 0 {
+		return nil, fmt.Errorf(MSG_ATTRIBUTE_REQUIRED, "primary keys of "+other.Name)
+	}
+
+	_, err := s.defineColumn(name, DETAIL, JSON, []et.Json{}, []byte{})
+	if err != nil {
+		return nil, err
+	}
+
+	junction, err := s.db.NewModel(s.Schema, through, max(s.Version, other.Version))
+	if err != nil {
+		return nil, err
+	}
+
+	junction.DefineCreatedAtField()
+	keys := map[string]string{}
+	parentKeys := map[string]string{}
+	for _, pk := range s.PrimaryKeys {
+		column := s.FindColumn(pk)
+		if column == nil {
+			return nil, fmt.Errorf(MSG_FIELD_NOT_FOUND, pk)
+		}
+
+		jf := fmt.Sprintf("%s_%s", s.Name, pk)
+		_, err = junction.DefineColumn(jf, column.TypeData, "")
+		if err != nil {
+			return nil, err
+		}
+		junction.DefinePrimaryKeys(jf)
+		keys[pk] = jf
+		parentKeys[jf] = pk
+	}
+
+	throughKeys := map[string]string{}
+	otherKeys := map[string]string{}
+	for _, pk := range other.PrimaryKeys {
+		column := other.FindColumn(pk)
+		if column == nil {
+			return nil, fmt.Errorf(MSG_FIELD_NOT_FOUND, pk)
+		}
+
+		jf := fmt.Sprintf("%s_%s", other.Name, pk)
+		if _, ok := parentKeys[jf]; ok {
+			jf = fmt.Sprintf("%s_%s", name, pk)
+		}
+		_, err = junction.DefineColumn(jf, column.TypeData, "")
+		if err != nil {
+			return nil, err
+		}
+		junction.DefinePrimaryKeys(jf)
+		throughKeys[jf] = pk
+		otherKeys[jf] = pk
+	}
+
+	err = junction.DefineForeignKey(s, parentKeys, true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = junction.DefineForeignKey(other, otherKeys, true, true)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := newDetail(other, keys, []interface{}{}, true, true)
+	detail.Through = junction.from()
+	detail.ThroughKeys = throughKeys
+	s.Details[name] = detail
+	return junction, nil
+}
+
+/**
+* manyToMany
+* @param name string
+* @return *Detail
+**/
+func (s *Model) manyToMany(name string) *Detail {
+	result, ok := s.Details[name]
+	if !ok || result.Through == nil {
+		return nil
+	}
+
+	return result
+}
+
+/**
+* throughModel
+* @param db *DB
+* @return *Model
+**/
+func (s *Detail) throughModel(db *DB) *Model {
+	if s.Through == nil {
+		return nil
+	}
+
+	if s.Through.model != nil {
+		return s.Through.model
+	}
+
+	if db == nil {
+		return nil
+	}
+
+	result, err := db.GetModel(s.Through.Key())
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
+
+/**
+* through
+* @param detail *Detail, data et.Json // filters the query by the links of data
+* @return *Ql
+**/
+func (s *Ql) through(detail *Detail, data et.Json) *Ql {
+	if len(s.Froms) == 0 {
+		return s
+	}
+
+	junction := s.Froms[0].model.bound(detail.throughModel(s.db))
+	if junction == nil {
+		return s
+	}
+
+	as := s.Froms[0].As
+	keys := map[string]string{}
+	for jf, pk := range detail.ThroughKeys {
+		keys[as+"."+pk] = jf
+	}
+
+	alias := s.alias("J")
+	n := len(s.Joins)
+	s.join(JOIN, junction, alias, keys)
+	if len(s.Joins) == n {
+		return s
+	}
+
+	s.Joins[n].Embed = false
+	for pk, jf := range detail.Keys {
+		s.Where(Eq(alias+"."+jf, data[pk]))
+	}
+
+	return s
+}
+
+/**
+* joinThrough
+* @param name string, detail *Detail // joins the junction and the related model as name
+* @return *Joins
+**/
+func (s *Ql) joinThrough(name string, detail *Detail) *Joins {
+	main := s.Froms[0]
+	junction := main.model.bound(detail.throughModel(s.db))
+	model := main.model.bound(detail.model(s.db))
+	if junction == nil || model == nil {
+		return nil
+	}
+
+	as := main.As
+	if as == "" {
+		as = main.Name
+	}
+
+	through := s.alias(name + "_through")
+	keys := map[string]string{}
+	for pk, jf := range detail.Keys {
+		keys[as+"."+pk] = jf
+	}
+
+	n := len(s.Joins)
+	s.join(LEFT, junction, through, keys)
+	if len(s.Joins) == n {
+		return nil
+	}
+	s.Joins[n].Embed = false
+
+	keys = map[string]string{}
+	for jf, pk := range detail.ThroughKeys {
+		keys[through+"."+jf] = pk
+	}
+
+	n = len(s.Joins)
+	s.join(LEFT, model, name, keys)
+	if len(s.Joins) == n {
+		return nil
+	}
+
+	result := s.Joins[n]
+	result.Embed = false
+	return result
+}
+
+/**
+* takeLinks
+* @param data et.Json
+* @return et.Json // the values of the many to many relations, removed from data
+**/
+func (s *Model) takeLinks(data et.Json) et.Json {
+	result := et.Json{}
+	for name := range s.Details {
+		if s.manyToMany(name) == nil {
+			continue
+		}
+
+		val, ok := data[name]
+		if !ok {
+			continue
+		}
+
+		result[name] = val
+		delete(data, name)
+	}
+
+	return result
+}
+
+/**
+* linkRow
+* @param detail *Detail, parent et.Json, id interface{}
+* @return et.Json
+**/
+func linkRow(detail *Detail, parent et.Json, id interface{}) et.Json {
+	result := et.Json{}
+	for pk, jf := range detail.Keys {
+		result[jf] = parent[pk]
+	}
+
+	item, isItem := id.(map[string]interface{})
+	if val, ok := id.(et.Json); ok {
+		item, isItem = val, true
+	}
+	for jf, pk := range detail.ThroughKeys {
+		if isItem {
+			result[jf] = item[pk]
+		} else {
+			result[jf] = id
+		}
+	}
+
+	return result
+}
+
+/**
+* toList
+* @param value interface{}
+* @return []interface{}
+**/
+func toList(value interface{}) []interface{} {
+	result := []interface{}{}
+	if value == nil {
+		return result
+	}
+
+	val := reflect.ValueOf(value)
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return append(result, value)
+	}
+
+	for i := 0; i < val.Len(); i++ {
+		result = append(result, val.Index(i).Interface())
+	}
+
+	return result
+}
+
+/**
+* link
+* @param tx *Tx, detail *Detail, parent et.Json, ids []interface{}
+* @return error
+**/
+func (s *Model) link(tx *Tx, detail *Detail, parent et.Json, ids []interface{}) error {
+	junction := s.bound(detail.throughModel(s.db))
+	if junction == nil {
+		return nil
+	}
+
+	for _, id := range ids {
+		row := linkRow(detail, parent, id)
+		_, err := junction.
+			Upsert(row).
+			ExecTx(tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/**
+* unlink
+* @param tx *Tx, detail *Detail, parent et.Json, ids []interface{}, all bool
+* @return error
+**/
+func (s *Model) unlink(tx *Tx, detail *Detail, parent et.Json, ids []interface{}, all bool) error {
+	junction := s.bound(detail.throughModel(s.db))
+	if junction == nil {
+		return nil
+	}
+
+	if all {
+		cmd := junction.Delete()
+		for pk, jf := range detail.Keys {
+			cmd.Where(Eq(jf, parent[pk]))
+		}
+		_, err := cmd.ExecTx(tx)
+		return err
+	}
+
+	for _, id := range ids {
+		cmd := junction.Delete()
+		for k, v := range linkRow(detail, parent, id) {
+			cmd.Where(Eq(k, v))
+		}
+		_, err := cmd.ExecTx(tx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/**
+* syncLinks
+* @param tx *Tx, parent, links et.Json // an array of ids replaces the links, {"link":[...], "unlink":[...]} changes them
+* @return error
+**/
+func (s *Model) syncLinks(tx *Tx, parent et.Json, links et.Json) error {
+	for name, value := range links {
+		detail := s.manyToMany(name)
+		if detail == nil {
+			continue
+		}
+
+		changes, ok := value.(map[string]interface{})
+		if val, isJson := value.(et.Json); isJson {
+			changes, ok = val, true
+		}
+
+		if !ok {
+			err := s.unlink(tx, detail, parent, nil, true)
+			if err != nil {
+				return err
+			}
+
+			err = s.link(tx, detail, parent, toList(value))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := s.unlink(tx, detail, parent, toList(changes["unlink"]), false)
+		if err != nil {
+			return err
+		}
+
+		err = s.link(tx, detail, parent, toList(changes["link"]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/**
+* alias
+* @param base string
+* @return string // base or base with a number, an alias not used by the froms of the query
+**/
+func (s *Ql) alias(base string) string {
+	used := func(as string) bool {
+		for _, from := range s.Froms {
+			if from.As == as || from.Name == as {
+				return true
+			}
+		}
+		return false
+	}
+
+	result := base
+	for i := 1; used(result); i++ {
+		result = fmt.Sprintf("%s%d", base, i)
+	}
+
+	return result
+}
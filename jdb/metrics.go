@@ -0,0 +1,130 @@
+package jdb
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type metricSeries struct {
+	labels  string
+	count   uint64
+	errors  uint64
+	rows    uint64
+	sum     float64
+	buckets []uint64
+}
+
+type Metrics struct {
+	buckets []float64
+	series  map[string]*metricSeries
+	mu      sync.Mutex
+}
+
+/**
+* NewMetrics
+* @param buckets ...float64 // histogram buckets in seconds, DefaultBuckets when empty
+* @return *Metrics
+**/
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+	return &Metrics{
+		buckets: buckets,
+		series:  make(map[string]*metricSeries),
+	}
+}
+
+/**
+* Observe
+* @param event QueryEvent // use it as the hook of DB.OnQuery
+**/
+func (s *Metrics) Observe(event QueryEvent) {
+	labels := fmt.Sprintf(`database=%q,model=%q,operation=%q`, event.Database, event.Model, event.Operation)
+	seconds := event.Duration.Seconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.series[labels]
+	if !ok {
+		item = &metricSeries{
+			labels:  labels,
+			buckets: make([]uint64, len(s.buckets)),
+		}
+		s.series[labels] = item
+	}
+
+	item.count++
+	item.sum += seconds
+	item.rows += uint64(event.Rows)
+	if event.Error != nil {
+		item.errors++
+	}
+	for i, bucket := range s.buckets {
+		if seconds <= bucket {
+			item.buckets[i]++
+		}
+	}
+}
+
+/**
+* ServeHTTP
+* @param w http.ResponseWriter, r *http.Request // prometheus text format
+**/
+func (s *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(s.String()))
+}
+
+/**
+* String
+* @return string
+**/
+func (s *Metrics) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.series))
+	for key := range s.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var result strings.Builder
+	result.WriteString("# TYPE jql_queries_total counter\n")
+	for _, key := range keys {
+		fmt.Fprintf(&result, "jql_queries_total{%s} %d\n", key, s.series[key].count)
+	}
+	result.WriteString("# TYPE jql_query_errors_total counter\n")
+	for _, key := range keys {
+		fmt.Fprintf(&result, "jql_query_errors_total{%s} %d\n", key, s.series[key].errors)
+	}
+	result.WriteString("# TYPE jql_query_rows_total counter\n")
+	for _, key := range keys {
+		fmt.Fprintf(&result, "jql_query_rows_total{%s} %d\n", key, s.series[key].rows)
+	}
+	result.WriteString("# TYPE jql_query_duration_seconds histogram\n")
+	for _, key := range keys {
+		item := s.series[key]
+		for i, bucket := range s.buckets {
+			le := strconv.FormatFloat(bucket, 'g', -1, 64)
+			fmt.Fprintf(&result, "jql_query_duration_seconds_bucket{%s,le=%q} %d\n", key, le, item.buckets[i])
+		}
+		fmt.Fprintf(&result, "jql_query_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", key, item.count)
+		fmt.Fprintf(&result, "jql_query_duration_seconds_sum{%s} %g\n", key, item.sum)
+		fmt.Fprintf(&result, "jql_query_duration_seconds_count{%s} %d\n", key, item.count)
+	}
+
+	return result.String()
+}
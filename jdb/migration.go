@@ -0,0 +1,463 @@
+package jdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/cgalvisleon/et/et"
+	"github.com/cgalvisleon/et/logs"
+	"github.com/cgalvisleon/et/timezone"
+)
+
+const MIGRATION_LOCK int64 = 7310582400
+
+type MigrationFunc func(db *DB, tx *Tx) error
+
+type Migration struct {
+	Version  int64         `json:"version"`
+	Name     string        `json:"name"`
+	Up       string        `json:"up"`
+	Down     string        `json:"down"`
+	Checksum string        `json:"checksum"`
+	upFn     MigrationFunc `json:"-"`
+	downFn   MigrationFunc `json:"-"`
+}
+
+/**
+* up
+* @param db *DB, tx *Tx
+* @return error
+**/
+func (s *Migration) up(db *DB, tx *Tx) error {
+	if s.upFn != nil {
+		return s.upFn(db, tx)
+	}
+
+	if s.Up == "" {
+		return nil
+	}
+
+	_, err := db.queryTx(context.Background(), tx, "", "migration", s.Up)
+	return err
+}
+
+/**
+* down
+* @param db *DB, tx *Tx
+* @return error
+**/
+func (s *Migration) down(db *DB, tx *Tx) error {
+	if s.downFn != nil {
+		return s.downFn(db, tx)
+	}
+
+	if s.Down == "" {
+		return nil
+	}
+
+	_, err := db.queryTx(context.Background(), tx, "", "migration", s.Down)
+	return err
+}
+
+var (
+	migrations *Model
+	registered []*Migration
+)
+
+func init() {
+	registered = make([]*Migration, 0)
+}
+
+/**
+* checksum
+* @param values ...string
+* @return string
+**/
+func checksum(values ...string) string {
+	hash := sha256.New()
+	for _, value := range values {
+		hash.Write([]byte(value))
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+/**
+* addMigration
+* @param migration *Migration
+* @return error
+**/
+func addMigration(migration *Migration) error {
+	idx := slices.IndexFunc(registered, func(m *Migration) bool { return m.Version == migration.Version })
+	if idx != -1 {
+		return fmt.Errorf(MSG_MIGRATION_EXISTS, migration.Version)
+	}
+
+	registered = append(registered, migration)
+	slices.SortFunc(registered, func(a, b *Migration) int {
+		if a.Version < b.Version {
+			return -1
+		}
+		if a.Version > b.Version {
+			return 1
+		}
+		return 0
+	})
+
+	return nil
+}
+
+/**
+* RegisterMigration
+* @param version int64, name string, up, down MigrationFunc
+* @return error
+**/
+func RegisterMigration(version int64, name string, up, down MigrationFunc) error {
+	return addMigration(&Migration{
+		Version:  version,
+		Name:     name,
+		Checksum: checksum(strconv.FormatInt(version, 10), name),
+		upFn:     up,
+		downFn:   down,
+	})
+}
+
+/**
+* RegisterSqlMigration
+* @param version int64, name, up, down string
+* @return error
+**/
+func RegisterSqlMigration(version int64, name, up, down string) error {
+	return addMigration(&Migration{
+		Version:  version,
+		Name:     name,
+		Up:       up,
+		Down:     down,
+		Checksum: checksum(up, down),
+	})
+}
+
+/**
+* LoadMigrations
+* @param dir string // files named <version>_<name>.up.sql and <version>_<name>.down.sql
+* @return error
+**/
+func LoadMigrations(dir string) error {
+	pattern := regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	files := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := pattern.FindStringSubmatch(entry.Name())
+		if len(matches) != 4 {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return err
+		}
+
+		bt, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		migration, ok := files[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			files[version] = migration
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(bt)
+		} else {
+			migration.Down = string(bt)
+		}
+	}
+
+	for _, migration := range files {
+		err := RegisterSqlMigration(migration.Version, migration.Name, migration.Up, migration.Down)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/**
+* CreateMigration
+* @param dir, name string
+* @return int64, error
+**/
+func CreateMigration(dir, name string) (int64, error) {
+	if name == "" {
+		return 0, fmt.Errorf(MSG_ATTRIBUTE_REQUIRED, "name")
+	}
+
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return 0, err
+	}
+
+	version := MigrationVersion(timezone.Now())
+	for _, kind := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%d_%s.%s.sql", version, name, kind))
+		err = os.WriteFile(path, []byte(fmt.Sprintf("-- %s %s\n", name, kind)), 0644)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return version, nil
+}
+
+/**
+* defineMigrations
+* @param db *DB
+* @return error
+**/
+func defineMigrations(db *DB) error {
+	if migrations != nil {
+		return nil
+	}
+
+	var err error
+	migrations, err = db.NewModel("core", "migrations", 1)
+	if err != nil {
+		return err
+	}
+	migrations.DefineCreatedAtField()
+	migrations.DefineColumn("version", INT, 0)
+	migrations.DefineColumn("name", TEXT, "")
+	migrations.DefineColumn("checksum", KEY, "")
+	migrations.DefinePrimaryKeys("version")
+	migrations.IsCore = true
+	if err = migrations.Init(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+/**
+* lockMigrations
+* @param tx *Tx
+* @return error
+**/
+func (s *DB) lockMigrations(tx *Tx) error {
+	sql, err := s.driver.Lock(MIGRATION_LOCK)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sqlTx(tx, sql)
+	return err
+}
+
+/**
+* appliedMigrations
+* @param tx *Tx
+* @return map[int64]et.Json, error
+**/
+func (s *DB) appliedMigrations(tx *Tx) (map[int64]et.Json, error) {
+	if migrations == nil {
+		return nil, fmt.Errorf(MSG_MODEL_NOT_FOUND, "migrations")
+	}
+
+	items, err := NewQuery(migrations, "A").
+		OrderBy("version").
+		AllTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[int64]et.Json{}
+	for _, item := range items.Result {
+		result[int64(item.Int("version"))] = item
+	}
+
+	return result, nil
+}
+
+/**
+* migrate
+* @param migration *Migration, up bool
+* @return bool, error
+**/
+func (s *DB) migrate(migration *Migration, up bool) (bool, error) {
+	tx := newTx()
+	err := s.lockMigrations(tx)
+	if err != nil {
+		return false, err
+	}
+
+	applied, err := s.appliedMigrations(tx)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	_, ok := applied[migration.Version]
+	if ok == up {
+		return false, tx.Commit()
+	}
+
+	if up {
+		err = migration.up(s, tx)
+	} else {
+		err = migration.down(s, tx)
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, fmt.Errorf(MSG_MIGRATION_FAILED, migration.Version, migration.Name, err)
+	}
+
+	if up {
+		_, err = migrations.
+			Insert(et.Json{
+				"created_at": timezone.Now(),
+				"version":    migration.Version,
+				"name":       migration.Name,
+				"checksum":   migration.Checksum,
+			}).
+			ExecTx(tx)
+	} else {
+		_, err = migrations.
+			Delete().
+			Where(Eq("version", migration.Version)).
+			ExecTx(tx)
+	}
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+/**
+* MigrateUp
+* @param target int64 // 0 applies every pending migration
+* @return int, error
+**/
+func (s *DB) MigrateUp(target int64) (int, error) {
+	result := 0
+	for _, migration := range registered {
+		if target > 0 && migration.Version > target {
+			break
+		}
+
+		ok, err := s.migrate(migration, true)
+		if err != nil {
+			return result, err
+		}
+
+		if ok {
+			result++
+			logs.Logf("migrate", MSG_MIGRATION_UP, migration.Version, migration.Name)
+		}
+	}
+
+	return result, nil
+}
+
+/**
+* MigrateDown
+* @param steps int
+* @return int, error
+**/
+func (s *DB) MigrateDown(steps int) (int, error) {
+	applied, err := s.appliedMigrations(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	result := 0
+	for i := len(registered) - 1; i >= 0 && result < steps; i-- {
+		migration := registered[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		ok, err := s.migrate(migration, false)
+		if err != nil {
+			return result, err
+		}
+
+		if ok {
+			result++
+			logs.Logf("migrate", MSG_MIGRATION_DOWN, migration.Version, migration.Name)
+		}
+	}
+
+	return result, nil
+}
+
+/**
+* MigrateStatus
+* @return []et.Json, error
+**/
+func (s *DB) MigrateStatus() ([]et.Json, error) {
+	applied, err := s.appliedMigrations(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []et.Json{}
+	for _, migration := range registered {
+		item := et.Json{
+			"version":  migration.Version,
+			"name":     migration.Name,
+			"checksum": migration.Checksum,
+			"applied":  false,
+			"dirty":    false,
+		}
+		if old, ok := applied[migration.Version]; ok {
+			item["applied"] = true
+			item["applied_at"] = old.Str("created_at")
+			item["dirty"] = old.Str("checksum") != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		result = append(result, item)
+	}
+
+	for version, old := range applied {
+		result = append(result, et.Json{
+			"version":    version,
+			"name":       old.Str("name"),
+			"checksum":   old.Str("checksum"),
+			"applied":    true,
+			"applied_at": old.Str("created_at"),
+			"missing":    true,
+		})
+	}
+
+	return result, nil
+}
+
+/**
+* MigrationVersion
+* @param t time.Time
+* @return int64
+**/
+func MigrationVersion(t time.Time) int64 {
+	result, _ := strconv.ParseInt(t.Format("20060102150405"), 10, 64)
+	return result
+}
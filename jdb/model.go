@@ -21,35 +21,47 @@ type TriggerFunction func(tx *Tx, old, new et.Json) error
 type DataContext func(tx *Tx, data et.Json)
 
 type Model struct {
-	Database      string                 `json:"database"`
-	Schema        string                 `json:"schema"`
-	Name          string                 `json:"name"`
-	Table         string                 `json:"table"`
-	Columns       []*Column              `json:"columns"`
-	SourceField   string                 `json:"source_field"`
-	IdxField      string                 `json:"idx_field"`
-	Indexes       []string               `json:"indexes"`
-	PrimaryKeys   []string               `json:"primary_keys"`
-	ForeignKeys   []*Detail              `json:"foreign_keys"`
-	Unique        []string               `json:"unique"`
-	Required      []string               `json:"required"`
-	Hidden        []string               `json:"hidden"`
-	Details       map[string]*Detail     `json:"details"`
-	Rollups       map[string]*Detail     `json:"rollups"`
-	Relations     map[string]*Detail     `json:"relations"`
-	IsStrict      bool                   `json:"is_strict"`
-	Version       int                    `json:"version"`
-	IsCore        bool                   `json:"is_core"`
-	IsDebug       bool                   `json:"-"`
-	isInit        bool                   `json:"-"`
-	beforeInserts []TriggerFunction      `json:"-"`
-	beforeUpdates []TriggerFunction      `json:"-"`
-	beforeDeletes []TriggerFunction      `json:"-"`
-	afterInserts  []TriggerFunction      `json:"-"`
-	afterUpdates  []TriggerFunction      `json:"-"`
-	afterDeletes  []TriggerFunction      `json:"-"`
-	calcs         map[string]DataContext `json:"-"`
-	db            *DB                    `json:"-"`
+	Database       string                     `json:"database"`
+	Schema         string                     `json:"schema"`
+	Name           string                     `json:"name"`
+	Table          string                     `json:"table"`
+	Columns        []*Column                  `json:"columns"`
+	SourceField    string                     `json:"source_field"`
+	IdxField       string                     `json:"idx_field"`
+	SearchField    string                     `json:"search_field"`
+	SearchLanguage string                     `json:"search_language"`
+	SearchFields   []string                   `json:"search_fields"`
+	Indexes        []string                   `json:"indexes"`
+	IndexSpecs     []*IndexSpec               `json:"index_specs"`
+	Checks         []*Check                   `json:"checks"`
+	PrimaryKeys    []string                   `json:"primary_keys"`
+	ForeignKeys    []*Detail                  `json:"foreign_keys"`
+	Unique         []string                   `json:"unique"`
+	Required       []string                   `json:"required"`
+	Hidden         []string                   `json:"hidden"`
+	Details        map[string]*Detail         `json:"details"`
+	Rollups        map[string]*Detail         `json:"rollups"`
+	Relations      map[string]*Detail         `json:"relations"`
+	IsStrict       bool                       `json:"is_strict"`
+	IsTenant       bool                       `json:"is_tenant"`
+	Version        int                        `json:"version"`
+	IsCore         bool                       `json:"is_core"`
+	IsDebug        bool                       `json:"-"`
+	isInit         bool                       `json:"-"`
+	beforeInserts  []TriggerFunction          `json:"-"`
+	beforeUpdates  []TriggerFunction          `json:"-"`
+	beforeDeletes  []TriggerFunction          `json:"-"`
+	afterInserts   []TriggerFunction          `json:"-"`
+	afterUpdates   []TriggerFunction          `json:"-"`
+	afterDeletes   []TriggerFunction          `json:"-"`
+	materialized   []*materialized            `json:"-"`
+	backfills      []*materialized            `json:"-"`
+	pending        []*Backfill                `json:"-"`
+	calcs          map[string]DataContext     `json:"-"`
+	validators     map[string][]ValidatorFunc `json:"-"`
+	db             *DB                        `json:"-"`
+	shared         *Model                     `json:"-"`
+	views          map[string]*Model          `json:"-"`
 }
 
 /**
@@ -139,6 +151,11 @@ func (s *Model) Init() error {
 		return nil
 	}
 
+	err = s.initRollups()
+	if err != nil {
+		return err
+	}
+
 	oldVersion, err := versionCatalog("model", s.Key())
 	if err != nil {
 		return err
@@ -151,6 +168,28 @@ func (s *Model) Init() error {
 	return nil
 }
 
+/**
+* Migrate
+* @return error // applies the definition again, the columns and indexes defined after Init are added to the table
+**/
+func (s *Model) Migrate() error {
+	if !s.isInit {
+		return s.Init()
+	}
+
+	if s.shared != nil {
+		*s = *s.shared.view(s.Schema)
+	}
+
+	err := s.db.loadModel(s)
+	if err != nil {
+		return err
+	}
+
+	s.isInit = true
+	return s.initRollups()
+}
+
 /**
 * Stricted
 * @return error
@@ -220,8 +259,8 @@ func (s *Model) FindColumn(name string) *Column {
 * @return *Field
 **/
 func (s *Model) FindField(name string) *Field {
-	pattern1 := regexp.MustCompile(`^([A-Za-z0-9>]+):([A-Za-z0-9]+)$`) // name:as
-	pattern2 := regexp.MustCompile(`^([A-Za-z0-9>]+)$`)                // name
+	pattern1 := regexp.MustCompile(`^([A-Za-z0-9_>]+):([A-Za-z0-9_]+)$`) // name:as
+	pattern2 := regexp.MustCompile(`^([A-Za-z0-9_>]+)$`)                 // name
 
 	if pattern1.MatchString(name) {
 		matches := pattern1.FindStringSubmatch(name)
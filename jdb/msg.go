@@ -10,6 +10,20 @@ var (
 	ErrModelNotFound         error  = errors.New("model not found")
 	ErrDbNotFound            error  = errors.New("database not found")
 	ErrNotUpdated            error  = errors.New("record not updated")
+	ErrNotInserted           error  = errors.New("record not inserted")
+	ErrNotFound              error  = errors.New("record not found")
+	ErrNotUpserted           error  = errors.New("record not inserted or updated")
+	ErrDuplicate             error  = errors.New("record duplicate")
+	ErrForeignKey            error  = errors.New("foreign key violation")
+	ErrNotNull               error  = errors.New("required value is null")
+	ErrCheck                 error  = errors.New("check constraint violation")
+	ErrSerialization         error  = errors.New("serialization failure")
+	ErrDeadlock              error  = errors.New("deadlock detected")
+	ErrValidation            error  = errors.New("validation failed")
+	ErrInvalidCursor         error  = errors.New("invalid cursor")
+	ErrTenantRequired        error  = errors.New("tenant required")
+	ErrCrossTenant           error  = errors.New("record of another tenant")
+	ErrNotConnected          error  = errors.New("database not connected")
 	MSG_DRIVER_NOT_FOUND     string = "driver not found"
 	MSG_NAME_REQUIRED        string = "name required"
 	MSG_COLUMN_EXISTS        string = "column %s already exists"
@@ -25,7 +39,23 @@ var (
 	MSG_SCHEMA_NOT_FOUND     string = "schema %s not found"
 	MSG_ROLLBACK_ERROR       string = "rollback error: %w: %s"
 	MSG_FIELD_NOT_FOUND      string = "field %s not found"
+	MSG_JOIN_KEYS_REQUIRED   string = "join keys required, no relation with %s"
 	MSG_DB_NOT_FOUND         string = "database %s not found"
+	MSG_STRUCT_REQUIRED      string = "struct required, got %s"
+	MSG_FIELD_SCAN_ERROR     string = "field %s scan error: %w"
+	MSG_MIGRATION_EXISTS     string = "migration %d already registered"
+	MSG_MIGRATION_FAILED     string = "migration %d %s failed: %w"
+	MSG_MIGRATION_UP         string = "Migration up:%d %s"
+	MSG_MIGRATION_DOWN       string = "Migration down:%d %s"
+	MSG_VALIDATION_REQUIRED  string = "%s is required"
+	MSG_VALIDATION_TYPE      string = "%s must be of type %s"
+	MSG_VALIDATION_LENGTH    string = "%s exceeds %d characters"
+	MSG_VALIDATION_ENUM      string = "%s must be one of %s"
+	MSG_VALIDATION_CHECK     string = "check %s failed"
+	MSG_SLOW_QUERY           string = "%dms %s model:%s operation:%s rows:%d\n%s"
+	MSG_SEARCH_NOT_DEFINED   string = "search not defined in model %s"
+	MSG_LANGUAGE_INVALID     string = "invalid search language %s"
+	MSG_INDEX_METHOD_INVALID string = "invalid index method %s"
 )
 
 func init() {
@@ -34,6 +64,21 @@ func init() {
 	case "es":
 		ErrModelNotFound = errors.New("modelo no encontrado")
 		ErrDbNotFound = errors.New("base de datos no encontrada")
+		ErrNotUpdated = errors.New("registro no actualizado")
+		ErrNotInserted = errors.New("registro no insertado")
+		ErrNotFound = errors.New("registro no encontrado")
+		ErrNotUpserted = errors.New("registro no insertado o actualizado")
+		ErrDuplicate = errors.New("registro duplicado")
+		ErrForeignKey = errors.New("violacion de llave foranea")
+		ErrNotNull = errors.New("valor requerido es nulo")
+		ErrCheck = errors.New("violacion de restriccion")
+		ErrSerialization = errors.New("fallo de serializacion")
+		ErrDeadlock = errors.New("bloqueo mutuo detectado")
+		ErrValidation = errors.New("validacion fallida")
+		ErrInvalidCursor = errors.New("cursor invalido")
+		ErrTenantRequired = errors.New("tenant requerido")
+		ErrCrossTenant = errors.New("registro de otro tenant")
+		ErrNotConnected = errors.New("base de datos no conectada")
 		MSG_DRIVER_NOT_FOUND = "driver no encontrado"
 		MSG_NAME_REQUIRED = "nombre requerido"
 		MSG_COLUMN_EXISTS = "columna %s ya existe"
@@ -49,6 +94,22 @@ func init() {
 		MSG_SCHEMA_NOT_FOUND = "schema %s no encontrado"
 		MSG_ROLLBACK_ERROR = "rollback error: %w: %s"
 		MSG_FIELD_NOT_FOUND = "field %s not found"
+		MSG_JOIN_KEYS_REQUIRED = "llaves del join requeridas, sin relacion con %s"
 		MSG_DB_NOT_FOUND = "database %s not found"
+		MSG_STRUCT_REQUIRED = "estructura requerida, se obtuvo %s"
+		MSG_FIELD_SCAN_ERROR = "error al leer el campo %s: %w"
+		MSG_MIGRATION_EXISTS = "migracion %d ya registrada"
+		MSG_MIGRATION_FAILED = "migracion %d %s fallo: %w"
+		MSG_MIGRATION_UP = "Migracion aplicada:%d %s"
+		MSG_MIGRATION_DOWN = "Migracion revertida:%d %s"
+		MSG_VALIDATION_REQUIRED = "%s es requerido"
+		MSG_VALIDATION_TYPE = "%s debe ser de tipo %s"
+		MSG_VALIDATION_LENGTH = "%s excede %d caracteres"
+		MSG_VALIDATION_ENUM = "%s debe ser uno de %s"
+		MSG_VALIDATION_CHECK = "restriccion %s fallida"
+		MSG_SLOW_QUERY = "%dms %s modelo:%s operacion:%s filas:%d\n%s"
+		MSG_SEARCH_NOT_DEFINED = "busqueda no definida en el modelo %s"
+		MSG_LANGUAGE_INVALID = "idioma de busqueda invalido %s"
+		MSG_INDEX_METHOD_INVALID = "metodo de indice invalido %s"
 	}
 }
@@ -0,0 +1,94 @@
+package jdb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cgalvisleon/et/logs"
+)
+
+type QueryEvent struct {
+	Database  string          `json:"database"`
+	Model     string          `json:"model"`
+	Operation string          `json:"operation"`
+	SQL       string          `json:"sql"`
+	Args      []any           `json:"args"`
+	Replica   string          `json:"replica"`
+	StartedAt time.Time       `json:"started_at"`
+	Duration  time.Duration   `json:"duration"`
+	Rows      int             `json:"rows"`
+	Error     error           `json:"-"`
+	TxId      string          `json:"tx_id"`
+	Ctx       context.Context `json:"-"`
+}
+
+type QueryHook func(event QueryEvent)
+
+var hooksMu sync.RWMutex
+
+/**
+* txId
+* @param tx *Tx
+* @return string
+**/
+func txId(tx *Tx) string {
+	if tx == nil {
+		return ""
+	}
+
+	return tx.Id
+}
+
+/**
+* OnQuery
+* @param fn QueryHook // receives every statement executed on the database, hooks run synchronously on the goroutine of the statement
+**/
+func (s *DB) OnQuery(fn QueryHook) {
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	s.hooks = append(s.hooks, fn)
+}
+
+/**
+* emit
+* @param event QueryEvent
+**/
+func (s *DB) emit(event QueryEvent) {
+	hooksMu.RLock()
+	hooks := s.hooks
+	hooksMu.RUnlock()
+
+	for _, fn := range hooks {
+		fn(event)
+	}
+}
+
+/**
+* SlowQueryLog
+* @param threshold time.Duration
+* @return QueryHook // logs the statements slower than threshold
+**/
+func SlowQueryLog(threshold time.Duration) QueryHook {
+	return func(event QueryEvent) {
+		if event.Duration < threshold {
+			return
+		}
+
+		logs.Logf("slow query", MSG_SLOW_QUERY, event.Duration.Milliseconds(), event.Database, event.Model, event.Operation, event.Rows, event.SQL)
+	}
+}
+
+/**
+* initHooks
+* @return // slow_query in milliseconds enables the slow query log
+**/
+func (s *DB) initHooks() {
+	threshold := s.Params.Int("slow_query")
+	if threshold <= 0 {
+		return
+	}
+
+	s.OnQuery(SlowQueryLog(time.Duration(threshold) * time.Millisecond))
+}
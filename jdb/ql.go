@@ -1,8 +1,12 @@
 package jdb
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	"github.com/cgalvisleon/et/envar"
 	"github.com/cgalvisleon/et/et"
@@ -18,29 +22,39 @@ const (
 )
 
 type Orders struct {
-	Field *Field `json:"field"`
-	Asc   bool   `json:"asc"`
+	Field   *Field `json:"field"`
+	Asc     bool   `json:"asc"`
+	Nearest any    `json:"nearest"`
 }
 
 type Ql struct {
-	Type     TypeQuery              `json:"type"`
-	Froms    []*From                `json:"froms"`
-	Selects  []*Field               `json:"select"`
-	Hiddens  []string               `json:"hidden"`
-	Wheres   *Wheres                `json:"wheres"`
-	Joins    []*Joins               `json:"joins"`
-	Details  map[string]*Detail     `json:"details"`
-	Rollups  map[string]*Detail     `json:"rollups"`
-	Calcs    map[string]DataContext `json:"calcs"`
-	GroupsBy []*Field               `json:"group_by"`
-	Havings  *Wheres                `json:"having"`
-	OrdersBy []*Orders              `json:"order_by"`
-	Page     int                    `json:"page"`
-	Rows     int                    `json:"rows"`
-	MaxRows  int                    `json:"max_rows"`
-	IsDebug  bool                   `json:"is_debug"`
-	db       *DB                    `json:"-"`
-	tx       *Tx                    `json:"-"`
+	Type       TypeQuery              `json:"type"`
+	Froms      []*From                `json:"froms"`
+	Selects    []*Field               `json:"select"`
+	Hiddens    []string               `json:"hidden"`
+	Wheres     *Wheres                `json:"wheres"`
+	Joins      []*Joins               `json:"joins"`
+	Details    map[string]*Detail     `json:"details"`
+	Rollups    map[string]*Detail     `json:"rollups"`
+	Calcs      map[string]DataContext `json:"calcs"`
+	GroupsBy   []*Field               `json:"group_by"`
+	Havings    *Wheres                `json:"having"`
+	OrdersBy   []*Orders              `json:"order_by"`
+	Page       int                    `json:"page"`
+	Rows       int                    `json:"rows"`
+	MaxRows    int                    `json:"max_rows"`
+	TextSearch *Search                `json:"search"`
+	Ctes       []*Cte                 `json:"with"`
+	Tree       *Tree                  `json:"tree"`
+	TenantId   string                 `json:"tenant_id"`
+	Cursor     *Cursor                `json:"cursor"`
+	IsDebug    bool                   `json:"is_debug"`
+	db         *DB                    `json:"-"`
+	tx         *Tx                    `json:"-"`
+	ctx        context.Context        `json:"-"`
+	primary    bool                   `json:"-"`
+	ttl        time.Duration          `json:"-"`
+	err        error                  `json:"-"`
 }
 
 /**
@@ -66,6 +80,7 @@ func NewQuery(model *Model, as string) *Ql {
 		GroupsBy: make([]*Field, 0),
 		Havings:  newWhere(),
 		OrdersBy: make([]*Orders, 0),
+		Ctes:     make([]*Cte, 0),
 		Page:     0,
 		Rows:     0,
 		MaxRows:  envar.GetInt("MAX_ROWS", 100),
@@ -148,15 +163,27 @@ func (s *Ql) addFrom(model *Model, as string) *From {
 func (s *Ql) findField(field interface{}) *Field {
 	switch v := field.(type) {
 	case string:
-		return findField(s.Froms, v)
-	case *Agg:
-		result := findField(s.Froms, v.Name())
-		if result != nil {
-			result.TypeColumn = AGG
-			result.Field = v
+		result := findField(s.Froms, v)
+		if result == nil && s.navigate(v) {
+			result = findField(s.Froms, v)
 		}
-		return result
+		return s.rollupField(result)
+	case *Agg:
+		return aggField(s.Froms, v)
 	case *Field:
+		if agg, ok := v.Field.(*Agg); ok && v.TypeColumn == AGG {
+			return aggField(s.Froms, agg)
+		}
+		if name, ok := v.Field.(string); ok && v.From == nil && name != "" {
+			result := s.findField(name)
+			if result == nil {
+				return v
+			}
+			if v.As != "" && v.As != name {
+				result.As = v.As
+			}
+			return result
+		}
 		return v
 	default:
 		return nil
@@ -177,18 +204,19 @@ func (s *Ql) Select(fields ...interface{}) *Ql {
 	}
 
 	for _, fld := range fields {
+		if name, ok := fld.(string); ok && s.Froms[0].model.relation(name) != nil {
+			s.Include(name)
+			continue
+		}
+
 		f := s.findField(fld)
 		if f == nil {
 			continue
 		}
 
 		switch f.TypeColumn {
-		case COLUMN:
-			s.Selects = append(s.Selects, f)
-		case AGG:
-			s.Selects = append(s.Selects, f)
-		case ATTRIB:
-			s.Selects = append(s.Selects, f)
+		case COLUMN, AGG, ATTRIB, SUBQUERY:
+			s.addSelect(f)
 		case DETAIL:
 			if f.From == nil {
 				continue
@@ -242,6 +270,31 @@ func (s *Ql) Select(fields ...interface{}) *Ql {
 	return s
 }
 
+/**
+* addSelect
+* @param field *Field // a join with selected fields is not embedded, an alias used by another from is prefixed with the alias of its from
+**/
+func (s *Ql) addSelect(field *Field) {
+	for _, join := range s.Joins {
+		if join.To == field.From {
+			join.Embed = false
+		}
+	}
+
+	for _, fld := range s.Selects {
+		if fld.As == field.As && fld.From != field.From && field.From != nil {
+			as := field.From.As
+			if as == "" {
+				as = field.From.Name
+			}
+			field.As = fmt.Sprintf("%s_%s", as, field.As)
+			break
+		}
+	}
+
+	s.Selects = append(s.Selects, field)
+}
+
 /**
 * Data
 * @return *Ql
@@ -258,25 +311,46 @@ func (s *Ql) Data(fields ...interface{}) *Ql {
 **/
 func (s *Ql) getDetails(tx *Tx, data et.Json) {
 	for name, dtl := range s.Details {
-		to := dtl.To
-		model, err := s.db.GetModel(to.Key())
-		if err != nil {
-			return
-		}
+		s.getDetail(tx, name, dtl, data)
+	}
+}
 
-		ql := NewQuery(model, "A").
-			Select(dtl.Select...)
+/**
+* getDetail
+* @param tx *Tx, name string, dtl *Detail, data et.Json
+**/
+func (s *Ql) getDetail(tx *Tx, name string, dtl *Detail, data et.Json) {
+	to := dtl.To
+	model, err := s.db.GetModel(to.Key())
+	if err != nil {
+		return
+	}
+
+	model = s.Froms[0].model.bound(model)
+	ql := NewQuery(model, "A").
+		Tenant(s.TenantId).
+		Select(dtl.Select...)
+	if dtl.Through != nil {
+		ql.through(dtl, data)
+	} else {
 		for pk, fk := range dtl.Keys {
 			val := data[pk]
 			ql.Where(Eq(fk, val))
 		}
-		result, err := ql.LimitTx(tx, dtl.Page, dtl.Rows)
-		if err != nil {
-			return
-		}
-
-		data[name] = result.Result
 	}
+	if dtl.Depth > 1 {
+		child := dtl.setLimit(dtl.Page, dtl.Rows)
+		child.Depth--
+		ql.Details[name] = child
+	}
+	ql.primary = s.primary
+	ql.ctx = s.ctx
+	result, err := ql.LimitTx(tx, dtl.Page, dtl.Rows)
+	if err != nil {
+		return
+	}
+
+	data[name] = result.Result
 }
 
 /**
@@ -287,18 +361,23 @@ func (s *Ql) getDetails(tx *Tx, data et.Json) {
 func (s *Ql) getRollups(tx *Tx, data et.Json) {
 	for name, dtl := range s.Rollups {
 		to := dtl.To
-		model, err := s.db.GetModel(to.Name)
+		model, err := s.db.GetModel(to.Key())
 		if err != nil {
 			return
 		}
 
+		model = s.Froms[0].model.bound(model)
+
 		flc := len(dtl.Select)
 		ql := NewQuery(model, "A").
+			Tenant(s.TenantId).
 			Select(dtl.Select...)
 		for pk, fk := range dtl.Keys {
 			val := data[pk]
 			ql.Where(Eq(fk, val))
 		}
+		ql.primary = s.primary
+		ql.ctx = s.ctx
 		result, err := ql.LimitTx(tx, dtl.Page, dtl.Rows)
 		if err != nil {
 			return
@@ -334,14 +413,44 @@ func (s *Ql) getCalls(tx *Tx, data et.Json) {
 	}
 }
 
+/**
+* selected
+* @param from *From
+* @return bool // true when a field of from is selected
+**/
+func (s *Ql) selected(from *From) bool {
+	for _, fld := range s.Selects {
+		if fld.From == from {
+			return true
+		}
+	}
+
+	return false
+}
+
 /**
 * Join
 * @param model *Model, as string, keys map[string]string
 * @return *Ql
 **/
 func (s *Ql) join(tp TypeJoin, model *Model, as string, keys map[string]string) *Ql {
+	if len(keys) == 0 {
+		keys = s.joinKeys(model)
+	}
+	if len(keys) == 0 {
+		s.err = fmt.Errorf(MSG_JOIN_KEYS_REQUIRED, model.Name)
+		return s
+	}
+
 	from := s.addFrom(model, as)
 	join := newJoins(tp, from, keys)
+	on, err := s.joinOn(from, keys)
+	if err != nil {
+		s.err = err
+		return s
+	}
+	join.On = on
+	join.Embed = !s.selected(from)
 	s.Joins = append(s.Joins, join)
 
 	return s
@@ -389,6 +498,7 @@ func (s *Ql) FullJoin(model *Model, as string, keys map[string]string) *Ql {
 * @return *Ql
 **/
 func (s *Ql) Where(condition *Condition) *Ql {
+	s.resolve(condition)
 	fld := s.findField(condition.Field)
 	if fld != nil {
 		condition.Field = fld
@@ -441,6 +551,7 @@ func (s *Ql) GroupBy(fields ...string) *Ql {
 **/
 func (s *Ql) Having(condition []*Condition) *Ql {
 	for _, cnd := range condition {
+		s.resolve(cnd)
 		fld := s.findField(cnd.Field)
 		if fld != nil {
 			cnd.Field = fld
@@ -492,6 +603,19 @@ func (s *Ql) OrderByDesc(fields ...string) *Ql {
 	return s.ordersBy(false, fields...)
 }
 
+/**
+* OrderByNearest
+* @param field string, geometry any // GeoJSON
+* @return *Ql
+**/
+func (s *Ql) OrderByNearest(field string, geometry any) *Ql {
+	fld := s.findField(field)
+	if fld != nil {
+		s.OrdersBy = append(s.OrdersBy, &Orders{Field: fld, Asc: true, Nearest: geometry})
+	}
+	return s
+}
+
 /**
 * Hidden
 * @param fields ...string
@@ -525,18 +649,40 @@ func (s *Ql) Current(data et.Json) *Ql {
 	return s
 }
 
+/**
+* Primary
+* @return *Ql // reads from the primary even outside a transaction
+**/
+func (s *Ql) Primary() *Ql {
+	s.primary = true
+	return s
+}
+
 /**
 * AllTx
 * @param tx *Tx
 * @return et.Items, error
 **/
 func (s *Ql) AllTx(tx *Tx) (et.Items, error) {
+	if s.err != nil {
+		return et.Items{}, s.err
+	}
+
+	if tx != nil {
+		s.tx = tx
+	}
+
 	sql, err := s.db.Ql(s)
 	if err != nil {
 		return et.Items{}, err
 	}
 
-	result, err := s.db.sqlTx(s.tx, sql)
+	key := s.cacheKey(sql)
+	if result, ok := s.fromCache(key); ok {
+		return result, nil
+	}
+
+	result, err := s.db.readTx(s.getContext(), s.tx, s.primary, fromKey(s.Froms[0]), string(s.Type), sql)
 	if err != nil {
 		return et.Items{}, err
 	}
@@ -554,6 +700,15 @@ func (s *Ql) AllTx(tx *Tx) (et.Items, error) {
 	}
 	wg.Wait()
 
+	if s.Cursor != nil && s.Cursor.Before {
+		slices.Reverse(result.Result)
+	}
+
+	if s.Tree != nil {
+		result = s.Tree.nest(result)
+	}
+
+	s.toCache(key, result)
 	return result, nil
 }
 
@@ -588,7 +743,7 @@ func (s *Ql) Limit(page, rows int) (et.Items, error) {
 /**
 * OneTx
 * @param tx *Tx
-* @return et.Item, error
+* @return et.Item, error // ErrNotFound when the query returns no row
 **/
 func (s *Ql) OneTx(tx *Tx) (et.Item, error) {
 	result, err := s.AllTx(tx)
@@ -596,6 +751,10 @@ func (s *Ql) OneTx(tx *Tx) (et.Item, error) {
 		return et.Item{}, err
 	}
 
+	if !result.Ok {
+		return et.Item{}, ErrNotFound
+	}
+
 	return et.Item{
 		Ok:     result.Ok,
 		Result: result.First(),
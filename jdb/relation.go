@@ -0,0 +1,151 @@
+package jdb
+
+import "strings"
+
+/**
+* relation
+* @param name string
+* @return *Detail // a relation or foreign key towards the model named name
+**/
+func (s *Model) relation(name string) *Detail {
+	if s == nil {
+		return nil
+	}
+
+	result, ok := s.Relations[name]
+	if ok {
+		return result
+	}
+
+	for _, foreignKey := range s.ForeignKeys {
+		if foreignKey.To.Name == name {
+			return foreignKey
+		}
+	}
+
+	return nil
+}
+
+/**
+* model
+* @return *Model
+**/
+func (s *Detail) model(db *DB) *Model {
+	if s.To.model != nil {
+		return s.To.model
+	}
+
+	if db == nil {
+		return nil
+	}
+
+	result, err := db.GetModel(s.To.Key())
+	if err != nil {
+		return nil
+	}
+
+	return result
+}
+
+/**
+* findJoin
+* @param as string
+* @return *Joins
+**/
+func (s *Ql) findJoin(as string) *Joins {
+	for _, join := range s.Joins {
+		if join.To.As == as {
+			return join
+		}
+	}
+
+	return nil
+}
+
+/**
+* joinRelation
+* @param name string
+* @return *Joins
+**/
+func (s *Ql) joinRelation(name string) *Joins {
+	result := s.findJoin(name)
+	if result != nil {
+		return result
+	}
+
+	if len(s.Froms) == 0 {
+		return nil
+	}
+
+	main := s.Froms[0]
+	if detail := main.model.manyToMany(name); detail != nil {
+		return s.joinThrough(name, detail)
+	}
+
+	detail := main.model.relation(name)
+	if detail == nil {
+		return nil
+	}
+
+	model := main.model.bound(detail.model(s.db))
+	if model == nil {
+		return nil
+	}
+
+	as := main.As
+	if as == "" {
+		as = main.Name
+	}
+
+	keys := map[string]string{}
+	for local, remote := range detail.Keys {
+		keys[as+"."+local] = remote
+	}
+
+	n := len(s.Joins)
+	s.join(LEFT, model, name, keys)
+	if len(s.Joins) == n {
+		return nil
+	}
+
+	result = s.Joins[n]
+	result.Embed = false
+	return result
+}
+
+/**
+* navigate
+* @param name string // relation.field
+* @return bool
+**/
+func (s *Ql) navigate(name string) bool {
+	idx := strings.Index(name, ".")
+	if idx == -1 {
+		return false
+	}
+
+	return s.joinRelation(name[:idx]) != nil
+}
+
+/**
+* Include
+* @param names ...string // relations embedded as nested objects
+* @return *Ql
+**/
+func (s *Ql) Include(names ...string) *Ql {
+	for _, name := range names {
+		if len(s.Froms) > 0 {
+			if detail := s.Froms[0].model.manyToMany(name); detail != nil {
+				s.Details[name] = detail.setLimit(detail.Page, detail.Rows)
+				continue
+			}
+		}
+
+		join := s.joinRelation(name)
+		if join != nil {
+			join.Embed = true
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,319 @@
+package jdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cgalvisleon/et/et"
+	"github.com/cgalvisleon/et/logs"
+	"github.com/cgalvisleon/et/timezone"
+)
+
+type Replica struct {
+	Host   string                 `json:"host"`
+	Port   int                    `json:"port"`
+	params et.Json                `json:"-"`
+	db     atomic.Pointer[sql.DB] `json:"-"`
+	ejects atomic.Int64           `json:"-"`
+	mu     sync.Mutex             `json:"-"`
+}
+
+type router struct {
+	replicas   []*Replica
+	next       atomic.Uint64
+	lastWrite  atomic.Int64
+	readWrites time.Duration
+	eject      time.Duration
+}
+
+/**
+* replicaParams
+* @param params et.Json
+* @return []et.Json // the replicas, each one inherits the params of the primary
+**/
+func replicaParams(params et.Json) []et.Json {
+	result := make([]et.Json, 0)
+	var items []et.Json
+	switch v := params["replicas"].(type) {
+	case []et.Json:
+		items = v
+	case []interface{}:
+		for _, item := range v {
+			switch r := item.(type) {
+			case et.Json:
+				items = append(items, r)
+			case map[string]interface{}:
+				items = append(items, et.Json(r))
+			}
+		}
+	}
+
+	for _, item := range items {
+		replica := et.Json{}
+		for k, v := range params {
+			if k != "replicas" {
+				replica[k] = v
+			}
+		}
+		for k, v := range item {
+			replica[k] = v
+		}
+		result = append(result, replica)
+	}
+
+	return result
+}
+
+/**
+* initReplicas
+* @return // replicas params, read_your_writes in milliseconds and replica_eject in seconds
+**/
+func (s *DB) initReplicas() {
+	items := replicaParams(s.Params)
+	if len(items) == 0 {
+		return
+	}
+
+	eject := s.Params.Int("replica_eject")
+	if eject <= 0 {
+		eject = 30
+	}
+	result := &router{
+		replicas:   make([]*Replica, 0),
+		readWrites: time.Duration(s.Params.Int("read_your_writes")) * time.Millisecond,
+		eject:      time.Duration(eject) * time.Second,
+	}
+	s.router = result
+	for _, params := range items {
+		replica := &Replica{
+			Host:   params.Str("host"),
+			Port:   params.Int("port"),
+			params: params,
+		}
+		result.replicas = append(result.replicas, replica)
+		if !s.connectReplica(replica) {
+			replica.ejects.Store(timezone.Now().Add(result.eject).UnixNano())
+		}
+	}
+}
+
+/**
+* connectReplica
+* @param replica *Replica
+* @return bool // false when the replica is unreachable, it is retried after the eject window
+**/
+func (s *DB) connectReplica(replica *Replica) bool {
+	replica.mu.Lock()
+	defer replica.mu.Unlock()
+
+	if replica.db.Load() != nil {
+		return true
+	}
+
+	db, err := s.driver.Replica(s, replica.params)
+	if err != nil {
+		logs.Errorf("replica %s:%d unreachable: %v", replica.Host, replica.Port, err)
+		return false
+	}
+
+	replica.db.Store(db)
+	return true
+}
+
+/**
+* markWrite
+* @return // starts the read-your-writes window, it is process-wide: any write of the DB sends every read to the primary
+**/
+func (s *DB) markWrite() {
+	if s.router == nil {
+		return
+	}
+
+	s.router.lastWrite.Store(timezone.Now().UnixNano())
+}
+
+/**
+* replica
+* @return *Replica // the next healthy replica, nil reads from the primary
+**/
+func (s *DB) replica() *Replica {
+	if s.router == nil {
+		return nil
+	}
+
+	now := timezone.Now().UnixNano()
+	if now-s.router.lastWrite.Load() < int64(s.router.readWrites) {
+		return nil
+	}
+
+	n := len(s.router.replicas)
+	for i := 0; i < n; i++ {
+		idx := s.router.next.Add(1) % uint64(n)
+		result := s.router.replicas[idx]
+		if result.ejects.Load() > now {
+			continue
+		}
+
+		if !s.connectReplica(result) {
+			result.ejects.Store(timezone.Now().Add(s.router.eject).UnixNano())
+			continue
+		}
+
+		return result
+	}
+
+	return nil
+}
+
+/**
+* eject
+* @param replica *Replica, err error
+* @return bool // true when the replica is down and was ejected
+**/
+func (s *DB) eject(replica *Replica, err error) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if replica.db.Load().PingContext(ctx) == nil {
+		return false
+	}
+
+	until := timezone.Now().Add(s.router.eject)
+	replica.ejects.Store(until.UnixNano())
+	logs.Errorf("replica %s:%d ejected: %v", replica.Host, replica.Port, err)
+	return true
+}
+
+/**
+* reader
+* @param tx *Tx, primary bool
+* @return *sql.DB, string // the pool of a read and the host of its replica, empty for the primary
+**/
+func (s *DB) reader(tx *Tx, primary bool) (*sql.DB, string) {
+	if tx != nil || primary {
+		return s.db, ""
+	}
+
+	replica := s.replica()
+	if replica == nil {
+		return s.db, ""
+	}
+
+	return replica.db.Load(), replica.Host
+}
+
+/**
+* readTx
+* @param ctx context.Context, tx *Tx, primary bool, model, operation, query string
+* @return et.Items, error // reads outside a transaction go to the replicas
+**/
+func (s *DB) readTx(ctx context.Context, tx *Tx, primary bool, model, operation, query string) (et.Items, error) {
+	if tx != nil || primary {
+		return s.queryTx(ctx, tx, model, operation, query)
+	}
+
+	replica := s.replica()
+	if replica == nil {
+		return s.queryTx(ctx, nil, model, operation, query)
+	}
+
+	start := timezone.Now()
+	rows, err := replica.db.Load().QueryContext(ctx, query)
+	if err != nil {
+		s.emit(QueryEvent{
+			Database:  s.Name,
+			Model:     model,
+			Operation: operation,
+			SQL:       query,
+			Replica:   replica.Host,
+			StartedAt: start,
+			Duration:  timezone.Now().Sub(start),
+			Error:     err,
+			Ctx:       ctx,
+		})
+		if s.eject(replica, err) {
+			return s.queryTx(ctx, nil, model, operation, query)
+		}
+		return et.Items{}, s.mapError(err)
+	}
+
+	result := RowsToItems(rows)
+	s.emit(QueryEvent{
+		Database:  s.Name,
+		Model:     model,
+		Operation: operation,
+		SQL:       query,
+		Replica:   replica.Host,
+		StartedAt: start,
+		Duration:  timezone.Now().Sub(start),
+		Rows:      result.Count,
+	})
+	return result, nil
+}
+
+/**
+* replicasHealth
+* @param ctx context.Context
+* @return []et.Json
+**/
+func (s *DB) replicasHealth(ctx context.Context) []et.Json {
+	result := make([]et.Json, 0)
+	if s.router == nil {
+		return result
+	}
+
+	for _, replica := range s.router.replicas {
+		db := replica.db.Load()
+		if db == nil {
+			result = append(result, et.Json{
+				"host":   replica.Host,
+				"port":   replica.Port,
+				"status": "down",
+			})
+			continue
+		}
+
+		status := "up"
+		err := db.PingContext(ctx)
+		if err != nil {
+			status = "down"
+		} else if replica.ejects.Load() > timezone.Now().UnixNano() {
+			status = "ejected"
+		}
+		stats := db.Stats()
+		result = append(result, et.Json{
+			"host":             replica.Host,
+			"port":             replica.Port,
+			"status":           status,
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"idle":             stats.Idle,
+		})
+	}
+
+	return result
+}
+
+/**
+* closeReplicas
+* @return error
+**/
+func (s *DB) closeReplicas() error {
+	if s.router == nil {
+		return nil
+	}
+
+	var result error
+	for _, replica := range s.router.replicas {
+		if db := replica.db.Swap(nil); db != nil {
+			result = errors.Join(result, db.Close())
+		}
+	}
+	s.router = nil
+
+	return result
+}
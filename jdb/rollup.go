@@ -0,0 +1,340 @@
+package jdb
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cgalvisleon/et/et"
+)
+
+/**
+* aggregate
+* @return *Agg // the aggregate of a rollup with a single aggregate select
+**/
+func (s *Detail) aggregate() *Agg {
+	if len(s.Select) != 1 {
+		return nil
+	}
+
+	result, ok := s.Select[0].(*Agg)
+	if !ok {
+		return nil
+	}
+
+	return result
+}
+
+/**
+* rollupQuery
+* @param parent *Model, detail *Detail, as string // as, alias of the parent in the outer query
+* @return *Ql
+**/
+func rollupQuery(parent *Model, detail *Detail, as string) *Ql {
+	model := parent.bound(detail.model(parent.db))
+	if model == nil {
+		return nil
+	}
+
+	result := NewQuery(model, "R").
+		Select(detail.aggregate())
+	for pk, fk := range detail.Keys {
+		result.Where(Eq(fk, Ref(as+"."+pk)))
+	}
+
+	return result
+}
+
+/**
+* rollupField
+* @param field *Field
+* @return *Field // aggregate rollups as correlated subqueries, so they can be filtered and sorted
+**/
+func (s *Ql) rollupField(field *Field) *Field {
+	if field == nil || field.TypeColumn != ROLLUP || field.From == nil || field.From.model == nil {
+		return field
+	}
+
+	name, ok := field.Field.(string)
+	if !ok {
+		return field
+	}
+
+	detail, ok := field.From.model.Rollups[name]
+	if !ok || detail.aggregate() == nil {
+		return field
+	}
+
+	as := field.From.As
+	if as == "" {
+		as = field.From.Name
+	}
+
+	ql := rollupQuery(field.From.model, detail, as)
+	if ql == nil {
+		return field
+	}
+
+	return &Field{
+		TypeColumn: SUBQUERY,
+		From:       field.From,
+		Field:      ql,
+		As:         field.As,
+	}
+}
+
+/**
+* rollupType
+* @param detail *Detail
+* @return TypeData
+**/
+func rollupType(detail *Detail) TypeData {
+	agg := detail.aggregate()
+	switch agg.Agg {
+	case "count":
+		return INT
+	case "sum", "avg":
+		return DECIMAL
+	case "min", "max":
+		if column := detail.To.model.FindColumn(agg.Field); column != nil {
+			return column.TypeData
+		}
+	}
+
+	return JSON
+}
+
+/**
+* MaterializeRollup
+* @param name string // keeps an aggregate rollup in a column of the model, synchronized by triggers of the detail
+* @return error
+**/
+func (s *Model) MaterializeRollup(name string) error {
+	detail, ok := s.Rollups[name]
+	if !ok {
+		return fmt.Errorf(MSG_FIELD_NOT_FOUND, name)
+	}
+
+	if detail.aggregate() == nil || detail.To.model == nil {
+		return fmt.Errorf(MSG_ATTRIBUTE_REQUIRED, "aggregate")
+	}
+
+	column := s.FindColumn(name)
+	if column == nil {
+		return fmt.Errorf(MSG_FIELD_NOT_FOUND, name)
+	}
+
+	column.TypeColumn = COLUMN
+	column.TypeData = rollupType(detail)
+	column.Default = ""
+	delete(s.Rollups, name)
+
+	item := &materialized{
+		parent: s,
+		name:   name,
+		detail: detail,
+	}
+	s.backfills = append(s.backfills, item)
+	detail.To.model.materialized = append(detail.To.model.materialized, item)
+	if !s.isInit {
+		return nil
+	}
+
+	return s.Migrate()
+}
+
+type materialized struct {
+	parent *Model
+	name   string
+	detail *Detail
+}
+
+type Backfill struct {
+	Column string `json:"column"`
+	Query  *Ql    `json:"query"`
+	Zero   any    `json:"zero"`
+}
+
+/**
+* zero
+* @return any // the value without details, nil for min and max
+**/
+func (s *materialized) zero() any {
+	switch s.detail.aggregate().Agg {
+	case "count", "sum", "avg":
+		return 0
+	}
+
+	return nil
+}
+
+/**
+* refresh
+* @param model *Model, tx *Tx, data et.Json // model is the detail that fired the command, a view resolves the parent in its schema
+* @return error
+**/
+func (s *materialized) refresh(model *Model, tx *Tx, data et.Json) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	agg := *s.detail.aggregate()
+	agg.Alias = s.name
+	tenantId, _ := data[TENANT_ID].(string)
+	ql := NewQuery(model, "A").
+		Tenant(tenantId).
+		Select(&agg)
+	parent := et.Json{}
+	for pk, fk := range s.detail.Keys {
+		val, ok := data[fk]
+		if !ok {
+			return nil
+		}
+		ql.Where(Eq(fk, val))
+		parent[pk] = val
+	}
+
+	item, err := ql.OneTx(tx)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	value := item.Result[s.name]
+	if value == nil {
+		value = s.zero()
+	}
+
+	cmd := model.bound(s.parent).
+		Update(et.Json{s.name: value}).
+		Tenant(tenantId)
+	for pk, val := range parent {
+		cmd.Where(Eq(pk, val))
+	}
+	_, err = cmd.ExecTx(tx)
+	return err
+}
+
+/**
+* sameKeys
+* @param old, new et.Json
+* @return bool // true when old and new belong to the same parent
+**/
+func (s *materialized) sameKeys(old, new et.Json) bool {
+	for _, fk := range s.detail.Keys {
+		if fmt.Sprint(old[fk]) != fmt.Sprint(new[fk]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+/**
+* syncRollups
+* @param tx *Tx, old, new et.Json
+* @return error // refreshes the materialized rollups of the parents of old and new
+**/
+func (s *Model) syncRollups(tx *Tx, old, new et.Json) error {
+	for _, item := range s.materialized {
+		if len(old) > 0 && len(new) > 0 && item.sameKeys(old, new) {
+			err := item.refresh(s, tx, new)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		err := item.refresh(s, tx, old)
+		if err != nil {
+			return err
+		}
+
+		err = item.refresh(s, tx, new)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/**
+* Backfills
+* @return []*Backfill // the materialized rollups pending to fill, each one is correlated with the table of the model
+**/
+func (s *Model) Backfills() []*Backfill {
+	return s.pending
+}
+
+/**
+* backfill
+* @return error // fills the materialized rollups of the existing rows once, when the model and its details are initialized
+**/
+func (s *Model) backfill() error {
+	if !s.isInit || catalog == nil {
+		return nil
+	}
+
+	items := make([]*materialized, 0)
+	for _, item := range s.backfills {
+		model := s.bound(item.detail.model(s.db))
+		if model == nil || !model.isInit {
+			continue
+		}
+
+		exists, err := existsCatalog("rollup", fmt.Sprintf("%s.%s", s.Key(), item.name))
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	s.pending = make([]*Backfill, 0, len(items))
+	for _, item := range items {
+		s.pending = append(s.pending, &Backfill{
+			Column: item.name,
+			Query:  rollupQuery(s, item.detail, s.Name),
+			Zero:   item.zero(),
+		})
+	}
+	err := s.db.mutateModel(s)
+	s.pending = nil
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		err := setCatalog("rollup", fmt.Sprintf("%s.%s", s.Key(), item.name), 1, item.name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/**
+* initRollups
+* @return error // backfills the rollups of the model and of the parents it feeds
+**/
+func (s *Model) initRollups() error {
+	err := s.backfill()
+	if err != nil {
+		return err
+	}
+
+	for _, item := range s.materialized {
+		err := s.bound(item.parent).backfill()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
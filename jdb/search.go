@@ -0,0 +1,134 @@
+package jdb
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/cgalvisleon/et/utility"
+)
+
+var SearchLanguages = []string{
+	"simple", "arabic", "armenian", "basque", "catalan", "danish", "dutch", "english", "finnish",
+	"french", "german", "greek", "hindi", "hungarian", "indonesian", "irish", "italian", "lithuanian",
+	"nepali", "norwegian", "portuguese", "romanian", "russian", "serbian", "spanish", "swedish",
+	"tamil", "turkish", "yiddish",
+}
+
+type Search struct {
+	Text      string   `json:"text"`
+	Language  string   `json:"language"`
+	Field     *Field   `json:"field"`
+	Rank      bool     `json:"rank"`
+	Highlight []*Field `json:"highlight"`
+}
+
+/**
+* ValidLanguage
+* @param language string
+* @return bool // true when language is in SearchLanguages, append the custom text search configurations there
+**/
+func ValidLanguage(language string) bool {
+	return slices.Contains(SearchLanguages, language)
+}
+
+/**
+* DefineSearch
+* @param language string, fields ...string
+* @return *Column, error
+**/
+func (s *Model) DefineSearch(language string, fields ...string) (*Column, error) {
+	if !utility.ValidStr(language, 0, []string{}) {
+		language = "simple"
+	}
+
+	if !ValidLanguage(language) {
+		return nil, fmt.Errorf(MSG_LANGUAGE_INVALID, language)
+	}
+
+	for _, name := range fields {
+		col := s.FindColumn(name)
+		if col == nil || (col.TypeColumn != COLUMN && col.TypeColumn != ATTRIB) {
+			return nil, fmt.Errorf(MSG_FIELD_NOT_FOUND, name)
+		}
+	}
+
+	result, err := s.defineColumn(SEARCH, COLUMN, TSVECTOR, "", []byte{})
+	if err != nil {
+		return nil, err
+	}
+
+	s.SearchField = SEARCH
+	s.SearchLanguage = language
+	s.SearchFields = fields
+	s.Indexes = utility.Add(s.Indexes, SEARCH)
+	s.Hidden = utility.Add(s.Hidden, SEARCH)
+	return result, nil
+}
+
+/**
+* Search
+* @param text string
+* @return *Ql
+**/
+func (s *Ql) Search(text string) *Ql {
+	if len(s.Froms) == 0 {
+		return s
+	}
+
+	from := s.Froms[0]
+	if from.model == nil || from.model.SearchField == "" {
+		s.err = fmt.Errorf(MSG_SEARCH_NOT_DEFINED, from.Name)
+		return s
+	}
+
+	field := from.findField(from.model.SearchField)
+	if field == nil {
+		s.err = fmt.Errorf(MSG_SEARCH_NOT_DEFINED, from.Name)
+		return s
+	}
+
+	s.TextSearch = &Search{
+		Text:      text,
+		Language:  from.model.SearchLanguage,
+		Field:     field,
+		Highlight: make([]*Field, 0),
+	}
+	s.Wheres.add(&Condition{
+		Field:    field,
+		Operator: OpSearch,
+		Value:    s.TextSearch,
+	})
+	return s
+}
+
+/**
+* OrderByRank
+* @return *Ql
+**/
+func (s *Ql) OrderByRank() *Ql {
+	if s.TextSearch == nil {
+		return s
+	}
+
+	s.TextSearch.Rank = true
+	return s
+}
+
+/**
+* Highlight
+* @param fields ...string
+* @return *Ql
+**/
+func (s *Ql) Highlight(fields ...string) *Ql {
+	if s.TextSearch == nil {
+		return s
+	}
+
+	for _, name := range fields {
+		fld := s.findField(name)
+		if fld != nil {
+			s.TextSearch.Highlight = append(s.TextSearch.Highlight, fld)
+		}
+	}
+	return s
+}
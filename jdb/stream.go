@@ -0,0 +1,336 @@
+package jdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync/atomic"
+
+	"github.com/cgalvisleon/et/et"
+	"github.com/cgalvisleon/et/timezone"
+)
+
+const STREAM_ROWS int = 500
+
+var (
+	cursorSeq  atomic.Int64
+	errStopped = errors.New("stream stopped")
+)
+
+/**
+* streamTx
+* @param ctx context.Context, tx *Tx, primary bool, model, query string, rows int, fn func(tx *Tx, items []et.Json) error // fn runs inside the Tx of the cursor
+* @return error
+**/
+func (s *DB) streamTx(ctx context.Context, tx *Tx, primary bool, model, query string, rows int, fn func(tx *Tx, items []et.Json) error) error {
+	own := tx == nil
+	db, replica := s.reader(tx, primary)
+	if own {
+		tx = newTx()
+	}
+
+	err := tx.Begin(db)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("jql_cursor_%d", cursorSeq.Add(1))
+	declare, fetch, close := s.driver.Cursor(name, query, rows)
+	fail := func(err error) error {
+		if own {
+			tx.Rollback()
+		}
+		return err
+	}
+
+	_, err = tx.Tx.ExecContext(ctx, declare)
+	if err != nil {
+		return fail(s.mapError(err))
+	}
+
+	for {
+		start := timezone.Now()
+		result, err := tx.Tx.QueryContext(ctx, fetch)
+		if err != nil {
+			s.emit(QueryEvent{Database: s.Name, Model: model, Operation: "stream", SQL: query, Replica: replica, StartedAt: start, Duration: timezone.Now().Sub(start), Error: err, TxId: tx.Id, Ctx: ctx})
+			return fail(s.mapError(err))
+		}
+
+		items := RowsToItems(result)
+		s.emit(QueryEvent{Database: s.Name, Model: model, Operation: "stream", SQL: query, Replica: replica, StartedAt: start, Duration: timezone.Now().Sub(start), Rows: items.Count, TxId: tx.Id, Ctx: ctx})
+		if items.Count > 0 {
+			err = fn(tx, items.Result)
+			if err != nil {
+				return fail(err)
+			}
+		}
+
+		if items.Count < rows {
+			break
+		}
+	}
+
+	_, err = tx.Tx.ExecContext(ctx, close)
+	if err != nil {
+		return fail(s.mapError(err))
+	}
+
+	if own {
+		return tx.Commit()
+	}
+
+	return nil
+}
+
+/**
+* EachTx
+* @param tx *Tx, fn func(item et.Json) error
+* @return error
+**/
+func (s *Ql) EachTx(tx *Tx, fn func(item et.Json) error) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.Rows > s.MaxRows {
+		s.MaxRows = s.Rows
+	}
+
+	if tx != nil {
+		s.tx = tx
+	}
+
+	sql, err := s.db.Ql(s)
+	if err != nil {
+		return err
+	}
+
+	return s.db.streamTx(s.getContext(), s.tx, s.primary, fromKey(s.Froms[0]), sql, STREAM_ROWS, func(tx *Tx, items []et.Json) error {
+		err := s.batchDetails(tx, items)
+		if err != nil {
+			return err
+		}
+
+		err = s.batchRollups(tx, items)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			s.getCalls(tx, item)
+		}
+
+		for _, item := range items {
+			err := fn(item)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+/**
+* Each
+* @param fn func(item et.Json) error
+* @return error
+**/
+func (s *Ql) Each(fn func(item et.Json) error) error {
+	return s.EachTx(nil, fn)
+}
+
+/**
+* IterTx
+* @param tx *Tx
+* @return iter.Seq2[et.Json, error]
+**/
+func (s *Ql) IterTx(tx *Tx) iter.Seq2[et.Json, error] {
+	return func(yield func(et.Json, error) bool) {
+		err := s.EachTx(tx, func(item et.Json) error {
+			if !yield(item, nil) {
+				return errStopped
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopped) {
+			yield(nil, err)
+		}
+	}
+}
+
+/**
+* Iter
+* @return iter.Seq2[et.Json, error]
+**/
+func (s *Ql) Iter() iter.Seq2[et.Json, error] {
+	return s.IterTx(nil)
+}
+
+/**
+* batchKey
+* @param data et.Json, keys []string
+* @return string
+**/
+func batchKey(data et.Json, keys []string) string {
+	result := ""
+	for _, key := range keys {
+		result += fmt.Sprintf("%v|", data[key])
+	}
+
+	return result
+}
+
+/**
+* batchLoad
+* @param tx *Tx, name string, dtl *Detail, items []et.Json, group bool
+* @return map[string][]et.Json, []string, error // the rows of the detail by parent, one query with IN for the batch
+**/
+func (s *Ql) batchLoad(tx *Tx, name string, dtl *Detail, items []et.Json, group bool) (map[string][]et.Json, []string, error) {
+	model, err := s.db.GetModel(dtl.To.Key())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	model = s.Froms[0].model.bound(model)
+	ql := NewQuery(model, "A").
+		Tenant(s.TenantId)
+	ql.primary = s.primary
+	ql.ctx = s.ctx
+	pks := make([]string, 0, len(dtl.Keys))
+	fks := make([]string, 0, len(dtl.Keys))
+	added := make([]string, 0)
+	for pk, fk := range dtl.Keys {
+		values := make([]interface{}, 0, len(items))
+		seen := map[string]bool{}
+		for _, item := range items {
+			key := fmt.Sprintf("%v", item[pk])
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			values = append(values, item[pk])
+		}
+		ql.Where(In(fk, values))
+		pks = append(pks, pk)
+		fks = append(fks, fk)
+		if len(dtl.Select) > 0 && !slices.Contains(dtl.Select, interface{}(fk)) {
+			added = append(added, fk)
+		}
+	}
+
+	if len(added) > 0 {
+		selects := make([]interface{}, 0, len(added)+len(dtl.Select))
+		for _, fk := range added {
+			selects = append(selects, fk)
+		}
+		ql.Select(append(selects, dtl.Select...)...)
+	} else {
+		ql.Select(dtl.Select...)
+	}
+	if group {
+		ql.GroupBy(fks...)
+	}
+	if dtl.Depth > 1 {
+		child := dtl.setLimit(dtl.Page, dtl.Rows)
+		child.Depth--
+		ql.Details[name] = child
+	}
+
+	result, err := ql.AllTx(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := make(map[string][]et.Json)
+	for _, item := range result.Result {
+		key := batchKey(item, fks)
+		for _, fk := range added {
+			delete(item, fk)
+		}
+		rows[key] = append(rows[key], item)
+	}
+
+	return rows, pks, nil
+}
+
+/**
+* batchPage
+* @param items []et.Json, page, rows, maxRows int
+* @return []et.Json // the page of the details of a parent
+**/
+func batchPage(items []et.Json, page, rows, maxRows int) []et.Json {
+	if rows > maxRows {
+		rows = maxRows
+	}
+
+	if rows <= 0 {
+		return items
+	}
+
+	offset := 0
+	if page > 0 {
+		offset = (page - 1) * rows
+	}
+	if offset >= len(items) {
+		return []et.Json{}
+	}
+
+	return items[offset:min(offset+rows, len(items))]
+}
+
+/**
+* batchDetails
+* @param tx *Tx, items []et.Json
+* @return error
+**/
+func (s *Ql) batchDetails(tx *Tx, items []et.Json) error {
+	for name, dtl := range s.Details {
+		if dtl.Through != nil {
+			for _, item := range items {
+				s.getDetail(tx, name, dtl, item)
+			}
+			continue
+		}
+
+		rows, pks, err := s.batchLoad(tx, name, dtl, items, false)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			item[name] = batchPage(rows[batchKey(item, pks)], dtl.Page, dtl.Rows, s.MaxRows)
+		}
+	}
+
+	return nil
+}
+
+/**
+* batchRollups
+* @param tx *Tx, items []et.Json
+* @return error
+**/
+func (s *Ql) batchRollups(tx *Tx, items []et.Json) error {
+	for name, dtl := range s.Rollups {
+		rows, pks, err := s.batchLoad(tx, name, dtl, items, dtl.aggregate() != nil)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			result := rows[batchKey(item, pks)]
+			if len(result) > 0 {
+				item[name] = result[0]
+			} else if len(dtl.Select) > 1 {
+				item[name] = et.Json{}
+			} else {
+				item[name] = ""
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,422 @@
+package jdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cgalvisleon/et/et"
+)
+
+const TagName = "jql"
+
+type structTag struct {
+	Name     string
+	TypeData TypeData
+	Pk       bool
+	Unique   bool
+	Required bool
+	Index    bool
+	Hidden   bool
+	Attrib   bool
+	Detail   map[string]string
+	IsDetail bool
+}
+
+/**
+* snakeCase
+* @param name string
+* @return string // UserID is user_id, HTTPServer is http_server
+**/
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var result strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				result.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		result.WriteRune(r)
+	}
+
+	return result.String()
+}
+
+/**
+* embedded
+* @param field reflect.StructField
+* @return bool // an embedded struct without a name in its tags, its fields are flattened
+**/
+func embedded(field reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+
+	tp := field.Type
+	if tp.Kind() == reflect.Pointer {
+		if !field.IsExported() {
+			return false
+		}
+		tp = tp.Elem()
+	}
+
+	if tp.Kind() != reflect.Struct || tp == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+
+	for _, name := range []string{TagName, "json"} {
+		if strings.Split(field.Tag.Get(name), ",")[0] != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+/**
+* parseTag
+* @param field reflect.StructField
+* @return *structTag
+**/
+func parseTag(field reflect.StructField) *structTag {
+	if !field.IsExported() {
+		return nil
+	}
+
+	tag, ok := field.Tag.Lookup(TagName)
+	if tag == "-" {
+		return nil
+	}
+
+	result := &structTag{
+		Name: snakeCase(field.Name),
+	}
+	if !ok {
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			return nil
+		}
+		if name != "" {
+			result.Name = name
+		}
+		return result
+	}
+
+	options := strings.Split(tag, ",")
+	if options[0] != "" {
+		result.Name = options[0]
+	}
+
+	for _, option := range options[1:] {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == "pk":
+			result.Pk = true
+		case option == "unique":
+			result.Unique = true
+		case option == "required":
+			result.Required = true
+		case option == "index":
+			result.Index = true
+		case option == "hidden":
+			result.Hidden = true
+		case option == "attrib":
+			result.Attrib = true
+		case strings.HasPrefix(option, "type="):
+			result.TypeData = TypeData(strings.TrimPrefix(option, "type="))
+		case strings.HasPrefix(option, "detail"):
+			result.IsDetail = true
+			result.Detail = map[string]string{}
+			keys := strings.TrimPrefix(strings.TrimPrefix(option, "detail"), "=")
+			for _, pair := range strings.Split(keys, ";") {
+				kv := strings.Split(pair, ":")
+				if len(kv) != 2 {
+					continue
+				}
+				result.Detail[kv[0]] = kv[1]
+			}
+		}
+	}
+
+	return result
+}
+
+/**
+* typeDataOf
+* @param tp reflect.Type
+* @return TypeData, interface{}
+**/
+func typeDataOf(tp reflect.Type) (TypeData, interface{}) {
+	for tp.Kind() == reflect.Pointer {
+		tp = tp.Elem()
+	}
+
+	if tp == reflect.TypeOf(time.Time{}) {
+		return DATETIME, ""
+	}
+
+	if tp == reflect.TypeOf(et.Json{}) {
+		return JSON, et.Json{}
+	}
+
+	switch tp.Kind() {
+	case reflect.String:
+		return TEXT, ""
+	case reflect.Bool:
+		return BOOLEAN, false
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return INT, 0
+	case reflect.Float32, reflect.Float64:
+		return FLOAT, 0.0
+	case reflect.Slice:
+		if tp.Elem().Kind() == reflect.Uint8 {
+			return BYTES, []byte{}
+		}
+		return JSON, []et.Json{}
+	case reflect.Map, reflect.Struct:
+		return JSON, et.Json{}
+	default:
+		return ANY, ""
+	}
+}
+
+/**
+* defineStruct
+* @param model *Model, tp reflect.Type
+* @return error
+**/
+func (s *Model) defineStruct(tp reflect.Type) error {
+	for tp.Kind() == reflect.Pointer {
+		tp = tp.Elem()
+	}
+
+	if tp.Kind() != reflect.Struct {
+		return fmt.Errorf(MSG_STRUCT_REQUIRED, tp.String())
+	}
+
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if embedded(field) {
+			err := s.defineStruct(field.Type)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag := parseTag(field)
+		if tag == nil {
+			continue
+		}
+
+		if tag.IsDetail {
+			to, err := s.DefineDetail(tag.Name, tag.Detail, s.Version)
+			if err != nil {
+				return err
+			}
+
+			elem := field.Type
+			for elem.Kind() == reflect.Pointer || elem.Kind() == reflect.Slice {
+				elem = elem.Elem()
+			}
+
+			if elem.Kind() == reflect.Struct {
+				err = to.defineStruct(elem)
+				if err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		tpData, defaultValue := typeDataOf(field.Type)
+		if tag.TypeData != "" {
+			tpData = tag.TypeData
+		} else if tpData == TEXT && (tag.Pk || tag.Index || tag.Unique) {
+			tpData = KEY
+		}
+
+		var err error
+		if tag.Attrib {
+			_, err = s.DefineAttribute(tag.Name, tpData, defaultValue)
+		} else {
+			_, err = s.DefineColumn(tag.Name, tpData, defaultValue)
+		}
+		if err != nil {
+			return err
+		}
+
+		if tag.Pk {
+			s.DefinePrimaryKeys(tag.Name)
+		}
+		if tag.Unique {
+			s.DefineUnique(tag.Name)
+		}
+		if tag.Required {
+			s.DefineRequired(tag.Name)
+		}
+		if tag.Index {
+			s.DefineIndex(tag.Name)
+		}
+		if tag.Hidden {
+			s.DefineHidden(tag.Name)
+		}
+	}
+
+	return nil
+}
+
+/**
+* DefineFromStruct
+* @param db *DB, schema, name string, version int
+* @return *Model, error
+**/
+func DefineFromStruct[T any](db *DB, schema, name string, version int) (*Model, error) {
+	result, err := db.NewModel(schema, name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var dest T
+	err = result.defineStruct(reflect.TypeOf(dest))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+/**
+* scanStruct
+* @param data et.Json, dest reflect.Value
+* @return error
+**/
+func scanStruct(data et.Json, dest reflect.Value) error {
+	for dest.Kind() == reflect.Pointer {
+		if dest.IsNil() {
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		dest = dest.Elem()
+	}
+
+	tp := dest.Type()
+	if tp.Kind() != reflect.Struct {
+		return fmt.Errorf(MSG_STRUCT_REQUIRED, tp.String())
+	}
+
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if embedded(field) {
+			err := scanStruct(data, dest.Field(i))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag := parseTag(field)
+		if tag == nil {
+			continue
+		}
+
+		val, ok := data[tag.Name]
+		if !ok || val == nil {
+			continue
+		}
+
+		bt, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+
+		err = json.Unmarshal(bt, dest.Field(i).Addr().Interface())
+		if err != nil {
+			return fmt.Errorf(MSG_FIELD_SCAN_ERROR, tag.Name, err)
+		}
+	}
+
+	return nil
+}
+
+/**
+* Scan
+* @param data et.Json
+* @return T, error
+**/
+func Scan[T any](data et.Json) (T, error) {
+	var result T
+	err := scanStruct(data, reflect.ValueOf(&result).Elem())
+	return result, err
+}
+
+/**
+* QueryTx
+* @param tx *Tx, ql *Ql
+* @return []T, error
+**/
+func QueryTx[T any](tx *Tx, ql *Ql) ([]T, error) {
+	items, err := ql.AllTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]T, 0, len(items.Result))
+	for _, item := range items.Result {
+		dest, err := Scan[T](item)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, dest)
+	}
+
+	return result, nil
+}
+
+/**
+* Query
+* @param ql *Ql
+* @return []T, error
+**/
+func Query[T any](ql *Ql) ([]T, error) {
+	return QueryTx[T](nil, ql)
+}
+
+/**
+* OneTx
+* @param tx *Tx, ql *Ql
+* @return T, bool, error
+**/
+func OneTx[T any](tx *Tx, ql *Ql) (T, bool, error) {
+	var result T
+	item, err := ql.OneTx(tx)
+	if errors.Is(err, ErrNotFound) {
+		return result, false, nil
+	}
+	if err != nil {
+		return result, false, err
+	}
+
+	result, err = Scan[T](item.Result)
+	if err != nil {
+		return result, false, err
+	}
+
+	return result, true, nil
+}
+
+/**
+* One
+* @param ql *Ql
+* @return T, bool, error
+**/
+func One[T any](ql *Ql) (T, bool, error) {
+	return OneTx[T](nil, ql)
+}
@@ -0,0 +1,258 @@
+package jdb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cgalvisleon/et/et"
+	"github.com/cgalvisleon/et/strs"
+)
+
+type jsonQuery et.Json
+
+/**
+* derivedModel
+* @param ql *Ql, as string
+* @return *Model
+**/
+func derivedModel(ql *Ql, as string) *Model {
+	result := &Model{
+		Name:        as,
+		Table:       as,
+		Columns:     make([]*Column, 0),
+		PrimaryKeys: make([]string, 0),
+		Hidden:      make([]string, 0),
+		Details:     make(map[string]*Detail),
+		Rollups:     make(map[string]*Detail),
+		Relations:   make(map[string]*Detail),
+		IsStrict:    true,
+		calcs:       make(map[string]DataContext),
+		validators:  make(map[string][]ValidatorFunc),
+		db:          ql.db,
+	}
+
+	for _, fld := range ql.Selects {
+		tp := ANY
+		if col := fld.Column(); col != nil {
+			tp = col.TypeData
+		}
+		result.Columns = append(result.Columns, newColumn(result, fld.As, COLUMN, tp, nil, []byte{}))
+	}
+
+	if len(ql.Froms) == 0 {
+		return result
+	}
+
+	for _, name := range ql.Froms[0].model.PrimaryKeys {
+		if result.idxColumn(name) != -1 {
+			result.PrimaryKeys = append(result.PrimaryKeys, name)
+		}
+	}
+
+	return result
+}
+
+/**
+* NewQueryFrom
+* @param ql *Ql, as string // derived table
+* @return *Ql
+**/
+func NewQueryFrom(ql *Ql, as string) *Ql {
+	if len(ql.Selects) == 0 && len(ql.Froms) > 0 {
+		inner := *ql
+		inner.Selects = make([]*Field, 0)
+		from := inner.Froms[0]
+		for _, col := range from.model.Columns {
+			if col.TypeColumn != COLUMN || slices.Contains(from.Hidden(), col.Name) {
+				continue
+			}
+			if fld := inner.findField(col.Name); fld != nil {
+				inner.Selects = append(inner.Selects, fld)
+			}
+		}
+		ql = &inner
+	}
+
+	model := derivedModel(ql, as)
+	result := NewQuery(model, as)
+	result.Froms[0].Query = ql
+	return result
+}
+
+/**
+* SelectQuery
+* @param ql *Ql, as string // scalar subquery
+* @return *Ql
+**/
+func (s *Ql) SelectQuery(ql *Ql, as string) *Ql {
+	s.Selects = append(s.Selects, &Field{
+		TypeColumn: SUBQUERY,
+		Field:      ql,
+		As:         as,
+	})
+	return s
+}
+
+/**
+* nested
+* @return []*Ql // the subqueries of froms, ctes, selects, joins and conditions
+**/
+func (s *Ql) nested() []*Ql {
+	result := []*Ql{}
+	add := func(ql *Ql) {
+		if ql != nil {
+			result = append(result, ql)
+		}
+	}
+
+	for _, from := range s.Froms {
+		add(from.Query)
+	}
+
+	for _, cte := range s.Ctes {
+		add(cte.Query)
+		add(cte.Recursive)
+	}
+
+	for _, fld := range s.Selects {
+		if ql, ok := fld.Field.(*Ql); ok {
+			add(ql)
+		}
+	}
+
+	conditions := append([]*Condition{}, s.Wheres.Conditions...)
+	conditions = append(conditions, s.Havings.Conditions...)
+	for _, join := range s.Joins {
+		conditions = append(conditions, join.On...)
+	}
+	for _, cond := range conditions {
+		if ql, ok := cond.Value.(*Ql); ok {
+			add(ql)
+		}
+		if cond.Field == nil {
+			continue
+		}
+		if ql, ok := cond.Field.Field.(*Ql); ok {
+			add(ql)
+		}
+	}
+
+	return result
+}
+
+/**
+* subquery
+* @param query et.Json // {"from": "schema.name", "as": "B", "select": ["B.id"], "where": [{"B.status": {"eq": "open"}}]}
+* @return *Ql, error
+**/
+func (s *Ql) subquery(query et.Json) (*Ql, error) {
+	var parent *From
+	if len(s.Froms) > 0 {
+		parent = s.Froms[0]
+	}
+
+	result, err := s.db.queryFrom(query, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	result.TenantId = s.TenantId
+	return result, nil
+}
+
+/**
+* queryFrom
+* @param query et.Json, parent *From // parent gives the schema and the version of a subquery, nil for a query
+* @return *Ql, error
+**/
+func (s *DB) queryFrom(query et.Json, parent *From) (*Ql, error) {
+	name := query.Str("from")
+	if name == "" {
+		return nil, fmt.Errorf(MSG_ATTRIBUTE_REQUIRED, "from")
+	}
+
+	if !strings.Contains(name, ".") && parent != nil {
+		name = fmt.Sprintf("%s.%s", parent.Schema, name)
+	}
+
+	model, err := s.GetModel(fmt.Sprintf("%s.%s", s.Name, name))
+	if err != nil {
+		return nil, err
+	}
+
+	if parent != nil && parent.model != nil {
+		model = parent.model.bound(model)
+	}
+
+	result := NewQuery(model, query.Str("as"))
+	if selects, ok := query["select"].([]interface{}); ok {
+		result.Select(selects...)
+	}
+
+	wheres, _ := query["where"].([]interface{})
+	for _, item := range wheres {
+		var cond *Condition
+		switch v := item.(type) {
+		case et.Json:
+			cond = ToCondition(v)
+		case map[string]interface{}:
+			cond = ToCondition(et.Json(v))
+		}
+		if cond != nil {
+			result.Where(cond)
+		}
+	}
+
+	return result, result.err
+}
+
+/**
+* resolve
+* @param condition *Condition // builds the subquery of a condition read from json
+**/
+func (s *Ql) resolve(condition *Condition) {
+	query, ok := condition.Value.(jsonQuery)
+	if !ok {
+		return
+	}
+
+	ql, err := s.subquery(et.Json(query))
+	if err != nil {
+		s.err = err
+		return
+	}
+
+	condition.Value = ql
+}
+
+/**
+* queryJson
+* @return et.Json // the json of a subquery, read back by subquery
+**/
+func (s *Ql) queryJson() et.Json {
+	result := et.Json{}
+	if len(s.Froms) > 0 {
+		from := s.Froms[0]
+		result["from"] = strs.Append(from.Schema, from.Name, ".")
+		result["as"] = from.As
+	}
+
+	selects := []interface{}{}
+	for _, fld := range s.Selects {
+		name := fld.Name()
+		if fld.From != nil && fld.From.As != "" {
+			name = fmt.Sprintf("%s.%s", fld.From.As, name)
+		}
+		selects = append(selects, name)
+	}
+	result["select"] = selects
+
+	wheres := []interface{}{}
+	for _, cond := range s.Wheres.Conditions {
+		wheres = append(wheres, cond.ToJson())
+	}
+	result["where"] = wheres
+
+	return result
+}
@@ -0,0 +1,252 @@
+package jdb
+
+import "context"
+
+type tenantKey struct{}
+
+/**
+* WithTenant
+* @param ctx context.Context, tenantId string
+* @return context.Context
+**/
+func WithTenant(ctx context.Context, tenantId string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantId)
+}
+
+/**
+* TenantFrom
+* @param ctx context.Context
+* @return string
+**/
+func TenantFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+
+	result, ok := ctx.Value(tenantKey{}).(string)
+	if !ok {
+		return ""
+	}
+
+	return result
+}
+
+/**
+* DefineTenantModel
+* @return *Model // rows are isolated by tenant_id
+**/
+func (s *Model) DefineTenantModel() *Model {
+	s.defineScopedModel(TENANT_ID)
+	s.DefineRequired(TENANT_ID)
+	s.IsTenant = true
+	return s
+}
+
+/**
+* SetTenant
+* @param tenantId string // the tenant of the queries and commands of the transaction
+* @return *Tx
+**/
+func (s *Tx) SetTenant(tenantId string) *Tx {
+	s.TenantId = tenantId
+	return s
+}
+
+/**
+* tenantCondition
+* @param from *From, tenantId string
+* @return *Condition
+**/
+func tenantCondition(from *From, tenantId string) *Condition {
+	result := Eq(&Field{
+		TypeColumn: COLUMN,
+		From:       from,
+		Field:      TENANT_ID,
+		As:         TENANT_ID,
+	}, tenantId)
+	result.Connector = AND
+	return result
+}
+
+/**
+* Tenant
+* @param tenantId string
+* @return *Ql
+**/
+func (s *Ql) Tenant(tenantId string) *Ql {
+	s.TenantId = tenantId
+	return s
+}
+
+/**
+* WithContext
+* @param ctx context.Context // takes the tenant and the parent span of the context
+* @return *Ql
+**/
+func (s *Ql) WithContext(ctx context.Context) *Ql {
+	s.ctx = ctx
+	if tenantId := TenantFrom(ctx); tenantId != "" {
+		s.TenantId = tenantId
+	}
+
+	return s
+}
+
+/**
+* getContext
+* @return context.Context // reported with the statements to the query hooks
+**/
+func (s *Ql) getContext() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+
+	return s.ctx
+}
+
+/**
+* tenant
+* @return string
+**/
+func (s *Ql) tenant() string {
+	if s.TenantId != "" {
+		return s.TenantId
+	}
+
+	if s.tx != nil {
+		return s.tx.TenantId
+	}
+
+	return ""
+}
+
+/**
+* Scope
+* @param from *From
+* @return []*Condition // the tenant conditions of from
+**/
+func (s *Ql) Scope(from *From) []*Condition {
+	if from == nil || from.model == nil || !from.model.IsTenant || s.TenantId == "" {
+		return []*Condition{}
+	}
+
+	return []*Condition{tenantCondition(from, s.TenantId)}
+}
+
+/**
+* scopeTenant
+* @param tenantId string // propagated to subqueries, derived tables and ctes
+* @return error
+**/
+func (s *Ql) scopeTenant(tenantId string) error {
+	if s.TenantId == "" {
+		s.TenantId = tenantId
+	}
+
+	for _, from := range s.Froms {
+		if from.model != nil && from.model.IsTenant && s.TenantId == "" {
+			return ErrTenantRequired
+		}
+	}
+
+	for _, ql := range s.nested() {
+		err := ql.scopeTenant(s.TenantId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+/**
+* Tenant
+* @param tenantId string
+* @return *Cmd
+**/
+func (s *Cmd) Tenant(tenantId string) *Cmd {
+	s.TenantId = tenantId
+	return s
+}
+
+/**
+* WithContext
+* @param ctx context.Context // takes the tenant and the parent span of the context
+* @return *Cmd
+**/
+func (s *Cmd) WithContext(ctx context.Context) *Cmd {
+	s.ctx = ctx
+	if tenantId := TenantFrom(ctx); tenantId != "" {
+		s.TenantId = tenantId
+	}
+
+	return s
+}
+
+/**
+* getContext
+* @return context.Context // reported with the statements to the query hooks
+**/
+func (s *Cmd) getContext() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+
+	return s.ctx
+}
+
+/**
+* tenant
+* @return string
+**/
+func (s *Cmd) tenant() string {
+	if s.TenantId != "" {
+		return s.TenantId
+	}
+
+	if s.tx != nil {
+		return s.tx.TenantId
+	}
+
+	return ""
+}
+
+/**
+* Scope
+* @return []*Condition // the tenant conditions of the command
+**/
+func (s *Cmd) Scope() []*Condition {
+	if !s.Model.IsTenant || s.TenantId == "" {
+		return []*Condition{}
+	}
+
+	return []*Condition{tenantCondition(nil, s.TenantId)}
+}
+
+/**
+* scopeTenant
+* @return error
+**/
+func (s *Cmd) scopeTenant() error {
+	if !s.Model.IsTenant {
+		return nil
+	}
+
+	s.TenantId = s.tenant()
+	if s.TenantId == "" {
+		return ErrTenantRequired
+	}
+
+	for _, data := range s.Data {
+		tenantId, ok := data[TENANT_ID]
+		if ok && tenantId != s.TenantId {
+			return ErrCrossTenant
+		}
+
+		if s.Type == INSERT || s.Type == UPSERT {
+			data[TENANT_ID] = s.TenantId
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package jdb
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	SpanOk    = "ok"
+	SpanError = "error"
+)
+
+type Span struct {
+	TraceId    string         `json:"trace_id"`
+	SpanId     string         `json:"span_id"`
+	ParentId   string         `json:"parent_id"`
+	Name       string         `json:"name"`
+	Kind       string         `json:"kind"`
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	Status     string         `json:"status"`
+	Message    string         `json:"message"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+type SpanExporter interface {
+	Export(span Span)
+}
+
+type SpanContext struct {
+	TraceId string `json:"trace_id"`
+	SpanId  string `json:"span_id"`
+}
+
+type spanKey struct{}
+
+/**
+* WithSpan
+* @param ctx context.Context, traceId, spanId string // the span of the caller, the parent of the statement spans
+* @return context.Context
+**/
+func WithSpan(ctx context.Context, traceId, spanId string) context.Context {
+	return context.WithValue(ctx, spanKey{}, SpanContext{TraceId: traceId, SpanId: spanId})
+}
+
+/**
+* SpanFrom
+* @param ctx context.Context
+* @return SpanContext, bool
+**/
+func SpanFrom(ctx context.Context) (SpanContext, bool) {
+	if ctx == nil {
+		return SpanContext{}, false
+	}
+
+	result, ok := ctx.Value(spanKey{}).(SpanContext)
+	if !ok || result.TraceId == "" {
+		return SpanContext{}, false
+	}
+
+	return result, true
+}
+
+type AsyncExporter struct {
+	exporter SpanExporter
+	spans    chan Span
+	dropped  atomic.Int64
+}
+
+/**
+* NewAsyncExporter
+* @param exporter SpanExporter, size int // size is the buffer of spans, 1000 when it is not positive
+* @return *AsyncExporter // exports on its own goroutine, the spans are dropped when the buffer is full
+**/
+func NewAsyncExporter(exporter SpanExporter, size int) *AsyncExporter {
+	if size <= 0 {
+		size = 1000
+	}
+
+	result := &AsyncExporter{
+		exporter: exporter,
+		spans:    make(chan Span, size),
+	}
+	go func() {
+		for span := range result.spans {
+			result.exporter.Export(span)
+		}
+	}()
+
+	return result
+}
+
+/**
+* Export
+* @param span Span
+**/
+func (s *AsyncExporter) Export(span Span) {
+	select {
+	case s.spans <- span:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+/**
+* Dropped
+* @return int64 // the spans dropped because the buffer was full
+**/
+func (s *AsyncExporter) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+/**
+* randomId
+* @param size int
+* @return string
+**/
+func randomId(size int) string {
+	bt := make([]byte, size)
+	rand.Read(bt)
+	return hex.EncodeToString(bt)
+}
+
+/**
+* traceId
+* @param txId string
+* @return string // the statements of a transaction share the trace
+**/
+func traceId(txId string) string {
+	if txId == "" {
+		return randomId(16)
+	}
+
+	sum := sha256.Sum256([]byte(txId))
+	return hex.EncodeToString(sum[:16])
+}
+
+/**
+* Tracer
+* @param exporter SpanExporter, system string // system is the driver, postgres
+* @return QueryHook // exports a client span with the OpenTelemetry database attributes, it runs on the statement goroutine, wrap slow exporters with NewAsyncExporter
+**/
+func Tracer(exporter SpanExporter, system string) QueryHook {
+	return func(event QueryEvent) {
+		name := event.Operation
+		if event.Model != "" {
+			name = name + " " + event.Model
+		}
+
+		attributes := map[string]any{
+			"db.system":    system,
+			"db.name":      event.Database,
+			"db.operation": event.Operation,
+			"db.statement": event.SQL,
+			"db.sql.table": event.Model,
+			"db.rows":      event.Rows,
+		}
+		if event.Replica != "" {
+			attributes["server.address"] = event.Replica
+		}
+		if event.TxId != "" {
+			attributes["db.tx_id"] = event.TxId
+		}
+
+		span := Span{
+			TraceId:    traceId(event.TxId),
+			SpanId:     randomId(8),
+			Name:       name,
+			Kind:       "client",
+			Start:      event.StartedAt,
+			End:        event.StartedAt.Add(event.Duration),
+			Status:     SpanOk,
+			Attributes: attributes,
+		}
+		if parent, ok := SpanFrom(event.Ctx); ok {
+			span.TraceId = parent.TraceId
+			span.ParentId = parent.SpanId
+		}
+		if event.Error != nil {
+			span.Status = SpanError
+			span.Message = event.Error.Error()
+		}
+
+		exporter.Export(span)
+	}
+}
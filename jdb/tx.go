@@ -13,7 +13,9 @@ type Tx struct {
 	EndAt     time.Time `json:"end_at"`
 	Id        string    `json:"id"`
 	Committed bool      `json:"committed"`
+	TenantId  string    `json:"tenant_id"`
 	Tx        *sql.Tx   `json:"-"`
+	commits   []func()  `json:"-"`
 }
 
 /**
@@ -78,8 +80,25 @@ func (s *Tx) Commit() error {
 	err := s.Tx.Commit()
 	s.Committed = true
 	s.EndAt = timezone.Now()
+	commits := s.commits
+	s.commits = nil
+	if err != nil {
+		return err
+	}
 
-	return err
+	for _, fn := range commits {
+		fn()
+	}
+
+	return nil
+}
+
+/**
+* afterCommit
+* @param fn func() // runs once the transaction commits, discarded on rollback
+**/
+func (s *Tx) afterCommit(fn func()) {
+	s.commits = append(s.commits, fn)
 }
 
 /**
@@ -98,6 +117,7 @@ func (s *Tx) Rollback() error {
 	err := s.Tx.Rollback()
 	s.Committed = true
 	s.EndAt = timezone.Now()
+	s.commits = nil
 
 	return err
 }
@@ -0,0 +1,390 @@
+package jdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cgalvisleon/et/et"
+)
+
+type ValidatorFunc func(value interface{}) error
+
+var typeLength = map[TypeData]int{
+	KEY:  80,
+	TEXT: 250,
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+var timeLayouts = []string{
+	"15:04:05.999999",
+	"15:04:05",
+	"15:04",
+}
+
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+type FieldError struct {
+	Field   string      `json:"field"`
+	Message string      `json:"message"`
+	Value   interface{} `json:"value"`
+}
+
+type ValidationError struct {
+	Model  string        `json:"model"`
+	Errors []*FieldError `json:"errors"`
+}
+
+/**
+* Error
+* @return string
+**/
+func (s *ValidationError) Error() string {
+	result := []string{}
+	for _, err := range s.Errors {
+		result = append(result, err.Message)
+	}
+
+	return fmt.Sprintf("%s, model:%s (%s)", ErrValidation.Error(), s.Model, strings.Join(result, "; "))
+}
+
+/**
+* Unwrap
+* @return error
+**/
+func (s *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+/**
+* add
+* @param field, message string, value interface{}
+**/
+func (s *ValidationError) add(field, message string, value interface{}) {
+	s.Errors = append(s.Errors, &FieldError{
+		Field:   field,
+		Message: message,
+		Value:   value,
+	})
+}
+
+/**
+* result
+* @return error
+**/
+func (s *ValidationError) result() error {
+	if len(s.Errors) == 0 {
+		return nil
+	}
+
+	return s
+}
+
+/**
+* DefineValidator
+* @param name string, fn ValidatorFunc
+* @return error
+**/
+func (s *Model) DefineValidator(name string, fn ValidatorFunc) error {
+	idx := s.idxColumn(name)
+	if idx == -1 {
+		return fmt.Errorf(MSG_FIELD_NOT_FOUND, name)
+	}
+
+	s.validators[name] = append(s.validators[name], fn)
+	return nil
+}
+
+/**
+* isEmpty
+* @param value interface{}
+* @return bool
+**/
+func isEmpty(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	default:
+		return false
+	}
+}
+
+/**
+* parseTime
+* @param value string, layouts []string
+* @return time.Time, bool
+**/
+func parseTime(value string, layouts []string) (time.Time, bool) {
+	for _, layout := range layouts {
+		result, err := time.Parse(layout, value)
+		if err == nil {
+			return result, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+/**
+* coerce
+* @param column *Column, value interface{}
+* @return interface{}, bool
+**/
+func coerce(column *Column, value interface{}) (interface{}, bool) {
+	tp := column.TypeData
+	switch tp {
+	case INT:
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return v, true
+		case float64:
+			return int64(v), v == float64(int64(v))
+		case float32:
+			return int64(v), v == float32(int64(v))
+		case json.Number:
+			result, err := v.Int64()
+			return result, err == nil
+		case string:
+			result, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			return result, err == nil
+		}
+		return value, false
+	case FLOAT:
+		switch v := value.(type) {
+		case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return v, true
+		case json.Number:
+			result, err := v.Float64()
+			return result, err == nil
+		case string:
+			result, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			return result, err == nil
+		}
+		return value, false
+	case BOOLEAN:
+		switch v := value.(type) {
+		case bool:
+			return v, true
+		case string:
+			result, err := strconv.ParseBool(strings.TrimSpace(v))
+			return result, err == nil
+		}
+		return value, false
+	case DATETIME:
+		switch v := value.(type) {
+		case time.Time:
+			return v, true
+		case string:
+			if v == "" {
+				return v, true
+			}
+			return parseTime(v, dateLayouts)
+		}
+		return value, false
+	case DATE:
+		switch v := value.(type) {
+		case time.Time:
+			return v.Format("2006-01-02"), true
+		case string:
+			result, ok := parseTime(v, dateLayouts)
+			return result.Format("2006-01-02"), ok
+		}
+		return value, false
+	case TIME:
+		switch v := value.(type) {
+		case time.Time:
+			return v.Format("15:04:05.999999"), true
+		case string:
+			result, ok := parseTime(v, timeLayouts)
+			return result.Format("15:04:05.999999"), ok
+		}
+		return value, false
+	case INTERVAL:
+		switch v := value.(type) {
+		case time.Duration, string:
+			return v, true
+		case int, int64:
+			return time.Duration(reflect.ValueOf(v).Int()) * time.Second, true
+		}
+		return value, false
+	case DECIMAL:
+		switch v := value.(type) {
+		case json.Number:
+			_, err := strconv.ParseFloat(v.String(), 64)
+			return v, err == nil
+		case string:
+			_, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			return strings.TrimSpace(v), err == nil
+		case float32:
+			return json.Number(strconv.FormatFloat(float64(v), 'f', -1, 32)), true
+		case float64:
+			return json.Number(strconv.FormatFloat(v, 'f', -1, 64)), true
+		case int, int8, int16, int32, int64:
+			return json.Number(strconv.FormatInt(reflect.ValueOf(v).Int(), 10)), true
+		case uint, uint8, uint16, uint32, uint64:
+			return json.Number(strconv.FormatUint(reflect.ValueOf(v).Uint(), 10)), true
+		}
+		return value, false
+	case UUID:
+		switch v := value.(type) {
+		case string:
+			return strings.ToLower(v), v == "" || uuidPattern.MatchString(v)
+		}
+		return value, false
+	case ENUM:
+		switch v := value.(type) {
+		case string:
+			return v, len(column.Values) == 0 || slices.Contains(column.Values, v)
+		}
+		return value, false
+	case ARRAY:
+		kind := reflect.TypeOf(value).Kind()
+		if kind != reflect.Slice && kind != reflect.Array {
+			return value, false
+		}
+		if column.Elem == "" {
+			return value, true
+		}
+		elem := &Column{TypeData: column.Elem}
+		items := reflect.ValueOf(value)
+		result := make([]interface{}, items.Len())
+		for i := 0; i < items.Len(); i++ {
+			val, ok := coerce(elem, items.Index(i).Interface())
+			if !ok {
+				return value, false
+			}
+			result[i] = val
+		}
+		return result, true
+	case KEY, TEXT, MEMO:
+		switch v := value.(type) {
+		case string:
+			return v, true
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+			return fmt.Sprintf("%v", v), true
+		}
+		return value, false
+	case BYTES:
+		switch v := value.(type) {
+		case []byte, string:
+			return v, true
+		}
+		return value, false
+	default:
+		return value, true
+	}
+}
+
+/**
+* validateTypes
+* @param data et.Json, result *ValidationError // the attributes are stored in jsonb, only a defined length limits them
+**/
+func (s *Model) validateTypes(data et.Json, result *ValidationError) {
+	for name, value := range data {
+		if value == nil {
+			continue
+		}
+
+		idx := s.idxColumn(name)
+		if idx == -1 {
+			continue
+		}
+
+		column := s.Columns[idx]
+		if column.TypeColumn != COLUMN && column.TypeColumn != ATTRIB {
+			continue
+		}
+
+		val, ok := coerce(column, value)
+		if !ok && column.TypeData == ENUM {
+			result.add(name, fmt.Sprintf(MSG_VALIDATION_ENUM, name, strings.Join(column.Values, ", ")), value)
+			continue
+		} else if !ok {
+			result.add(name, fmt.Sprintf(MSG_VALIDATION_TYPE, name, column.TypeData), value)
+			continue
+		}
+
+		length, ok := typeLength[column.TypeData]
+		if column.TypeColumn == ATTRIB {
+			ok = false
+		}
+		if column.Length > 0 {
+			length, ok = column.Length, true
+		}
+		if ok {
+			if str, ok := val.(string); ok && len([]rune(str)) > length {
+				result.add(name, fmt.Sprintf(MSG_VALIDATION_LENGTH, name, length), value)
+				continue
+			}
+		}
+
+		data[name] = val
+		for _, fn := range s.validators[name] {
+			err := fn(val)
+			if err != nil {
+				result.add(name, err.Error(), value)
+				break
+			}
+		}
+	}
+}
+
+/**
+* validateRequired
+* @param data et.Json, partial bool, result *ValidationError
+**/
+func (s *Model) validateRequired(data et.Json, partial bool, result *ValidationError) {
+	for _, name := range s.Required {
+		value, ok := data[name]
+		if !ok && partial {
+			continue
+		}
+
+		if isEmpty(value) && !slices.ContainsFunc(result.Errors, func(e *FieldError) bool { return e.Field == name }) {
+			result.add(name, fmt.Sprintf(MSG_VALIDATION_REQUIRED, name), value)
+		}
+	}
+}
+
+/**
+* Validate
+* @param data et.Json, partial bool // data is not changed
+* @return error
+**/
+func (s *Model) Validate(data et.Json, partial bool) error {
+	_, err := s.validate(data, partial)
+	return err
+}
+
+/**
+* validate
+* @param data et.Json, partial bool
+* @return et.Json, error // a copy of data with the values coerced to the types of the columns
+**/
+func (s *Model) validate(data et.Json, partial bool) (et.Json, error) {
+	data = data.Clone()
+	result := &ValidationError{Model: s.Name}
+	s.validateTypes(data, result)
+	s.validateRequired(data, partial, result)
+	s.validateChecks(data, result)
+	err := result.result()
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,175 @@
+package jdb
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/cgalvisleon/et/utility"
+)
+
+var views sync.Mutex
+
+/**
+* Bind
+* @param schema string
+* @return *Model, error // a view of the model with its table in schema, sharing definition and connection
+**/
+func (s *Model) Bind(schema string) (*Model, error) {
+	if s.shared != nil {
+		return s.shared.Bind(schema)
+	}
+
+	if !utility.ValidStr(schema, 0, []string{}) {
+		return nil, errors.New(MSG_NAME_REQUIRED)
+	}
+
+	schema = utility.Normalize(schema)
+	if schema == s.Schema {
+		return s, nil
+	}
+
+	views.Lock()
+	result, ok := s.views[schema]
+	views.Unlock()
+	if ok {
+		return result, nil
+	}
+
+	result = s.view(schema)
+	err := result.Init()
+	if err != nil {
+		return nil, err
+	}
+
+	views.Lock()
+	defer views.Unlock()
+	if s.views == nil {
+		s.views = make(map[string]*Model)
+	}
+	s.views[schema] = result
+	return result, nil
+}
+
+/**
+* view
+* @param schema string
+* @return *Model // a copy of the definition with its table in schema
+**/
+func (s *Model) view(schema string) *Model {
+	view := *s
+	result := &view
+	result.Schema = schema
+	result.Table = ""
+	result.isInit = false
+	result.shared = s
+	result.views = nil
+	result.ForeignKeys = make([]*Detail, len(s.ForeignKeys))
+	for i, foreignKey := range s.ForeignKeys {
+		to := *foreignKey.To
+		to.Schema = schema
+		to.Table = ""
+		detail := foreignKey.setLimit(foreignKey.Page, foreignKey.Rows)
+		detail.To = &to
+		result.ForeignKeys[i] = detail
+	}
+
+	return result
+}
+
+/**
+* Shared
+* @return *Model // the model a view was bound from, or the model itself
+**/
+func (s *Model) Shared() *Model {
+	if s.shared != nil {
+		return s.shared
+	}
+
+	return s
+}
+
+/**
+* bound
+* @param model *Model
+* @return *Model // model in the schema of s, when s is a view
+**/
+func (s *Model) bound(model *Model) *Model {
+	if s == nil || s.shared == nil || model == nil {
+		return model
+	}
+
+	result, err := model.Bind(s.Schema)
+	if err != nil {
+		return model
+	}
+
+	return result
+}
+
+/**
+* DropSchema
+* @param name string
+* @return error
+**/
+func (s *DB) DropSchema(name string) error {
+	sql, err := s.driver.DropSchema(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sqlTx(nil, sql)
+	if err != nil {
+		return err
+	}
+
+	return s.releaseSchema(name)
+}
+
+/**
+* ArchiveSchema
+* @param name, newName string
+* @return error
+**/
+func (s *DB) ArchiveSchema(name, newName string) error {
+	sql, err := s.driver.RenameSchema(name, utility.Normalize(newName))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.sqlTx(nil, sql)
+	if err != nil {
+		return err
+	}
+
+	return s.releaseSchema(name)
+}
+
+/**
+* releaseSchema
+* @param name string // forgets the views bound to the schema
+* @return error
+**/
+func (s *DB) releaseSchema(name string) error {
+	views.Lock()
+	defer views.Unlock()
+
+	for _, model := range models {
+		if model.db != s || model.views == nil {
+			continue
+		}
+
+		view, ok := model.views[name]
+		if !ok {
+			continue
+		}
+
+		delete(model.views, name)
+		err := deleteCatalog("model", view.Key())
+		if err != nil {
+			return err
+		}
+	}
+
+	delete(s.Schemas, name)
+	return nil
+}
@@ -0,0 +1,143 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgalvisleon/et/envar"
+	"github.com/cgalvisleon/et/et"
+	_ "github.com/cgalvisleon/jql/drivers/postgres"
+	"github.com/cgalvisleon/jql/jdb"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dir    string
+	target int64
+	steps  int
+)
+
+var Migrate = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply, revert or create database migrations.",
+	Long:  "Versioned migrations, SQL files in the migrations folder or Go functions registered in the binary, recorded in core.migrations.",
+}
+
+var CmdUp = &cobra.Command{
+	Use:   "up",
+	Short: "Apply pending migrations.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := connect()
+		if err != nil {
+			return fmt.Errorf("connect failed: %w", err)
+		}
+
+		n, err := db.MigrateUp(target)
+		if err != nil {
+			return fmt.Errorf("migrate failed: %w", err)
+		}
+
+		fmt.Printf("%d migrations applied\n", n)
+		return nil
+	},
+}
+
+var CmdDown = &cobra.Command{
+	Use:   "down",
+	Short: "Revert applied migrations.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := connect()
+		if err != nil {
+			return fmt.Errorf("connect failed: %w", err)
+		}
+
+		n, err := db.MigrateDown(steps)
+		if err != nil {
+			return fmt.Errorf("migrate failed: %w", err)
+		}
+
+		fmt.Printf("%d migrations reverted\n", n)
+		return nil
+	},
+}
+
+var CmdStatus = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of every migration.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := connect()
+		if err != nil {
+			return fmt.Errorf("connect failed: %w", err)
+		}
+
+		items, err := db.MigrateStatus()
+		if err != nil {
+			return fmt.Errorf("status failed: %w", err)
+		}
+
+		for _, item := range items {
+			state := "pending"
+			if item.Bool("missing") {
+				state = "missing"
+			} else if item.Bool("dirty") {
+				state = "dirty"
+			} else if item.Bool("applied") {
+				state = "applied"
+			}
+			fmt.Printf("%v\t%-8s\t%s\n", item["version"], state, item.Str("name"))
+		}
+		return nil
+	},
+}
+
+var CmdCreate = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create a new pair of up/down migration files.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, err := jdb.CreateMigration(dir, args[0])
+		if err != nil {
+			return fmt.Errorf("create failed: %w", err)
+		}
+
+		fmt.Printf("Migration %d_%s created in %s\n", version, args[0], dir)
+		return nil
+	},
+}
+
+func init() {
+	Migrate.PersistentFlags().StringVar(&dir, "dir", envar.GetStr("DB_MIGRATIONS", "migrations"), "migrations folder")
+	CmdUp.Flags().Int64Var(&target, "to", 0, "apply up to this version")
+	CmdDown.Flags().IntVar(&steps, "steps", 1, "number of migrations to revert")
+	Migrate.AddCommand(CmdUp, CmdDown, CmdStatus, CmdCreate)
+}
+
+/**
+* connect
+* @return *jdb.DB, error
+**/
+func connect() (*jdb.DB, error) {
+	err := jdb.LoadMigrations(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	name := envar.GetStr("DB_NAME", "josephine")
+	params := et.Json{
+		"driver":          envar.GetStr("DB_DRIVER", jdb.DriverPostgres),
+		"database":        name,
+		"host":            envar.GetStr("DB_HOST", "localhost"),
+		"port":            envar.GetInt("DB_PORT", 5432),
+		"username":        envar.GetStr("DB_USERNAME", "test"),
+		"password":        envar.GetStr("DB_PASSWORD", "test"),
+		"app":             envar.GetStr("DB_APP", "jql"),
+		"use_core":        true,
+		"sslmode":         envar.GetStr("DB_SSLMODE", "disable"),
+		"sslrootcert":     envar.GetStr("DB_SSLROOTCERT", ""),
+		"sslcert":         envar.GetStr("DB_SSLCERT", ""),
+		"sslkey":          envar.GetStr("DB_SSLKEY", ""),
+		"connect_timeout": envar.GetInt("DB_CONNECT_TIMEOUT", 10),
+	}
+
+	return jdb.Connect(name, params)
+}
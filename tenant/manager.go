@@ -0,0 +1,537 @@
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/cgalvisleon/et/et"
+	"github.com/cgalvisleon/et/timezone"
+	"github.com/cgalvisleon/et/utility"
+	"github.com/cgalvisleon/jql/jdb"
+)
+
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+	StatusArchived  = "archived"
+	StatusFailed    = "failed"
+	ModeDatabase    = "database"
+	ModeSchema      = "schema"
+)
+
+type TemplateFunc func(db *jdb.DB) (*jdb.Model, error)
+
+type Template struct {
+	Name    string       `json:"name"`
+	Version int          `json:"version"`
+	fn      TemplateFunc `json:"-"`
+}
+
+var (
+	registry   *jdb.Model
+	shared     *jdb.DB
+	defaults   et.Json
+	templates  []*Template
+	mu         sync.Mutex
+	migrations sync.Mutex
+)
+
+/**
+* Init
+* @param core *jdb.DB, params et.Json // connection params shared by the tenants, core also holds the schema tenants
+* @return error
+**/
+func Init(core *jdb.DB, params et.Json) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if registry != nil {
+		return nil
+	}
+
+	model, err := core.NewModel("core", "tenants", 1)
+	if err != nil {
+		return err
+	}
+	model.DefineCreatedAtField()
+	model.DefineUpdatedAtField()
+	model.DefineColumn("id", jdb.KEY, "")
+	model.DefineColumn("mode", jdb.KEY, "")
+	model.DefineColumn("database", jdb.KEY, "")
+	model.DefineColumn("schema", jdb.KEY, "")
+	model.DefineColumn("status", jdb.KEY, "")
+	model.DefineColumn("params", jdb.JSON, et.Json{})
+	model.DefineColumn("versions", jdb.JSON, et.Json{})
+	model.DefineColumn("error", jdb.MEMO, "")
+	model.DefineColumn("migrated_at", jdb.DATETIME, "")
+	model.DefinePrimaryKeys("id")
+	model.IsCore = true
+	err = model.Init()
+	if err != nil {
+		return err
+	}
+
+	registry = model
+	shared = core
+	defaults = params
+	return nil
+}
+
+/**
+* RegisterModel
+* @param name string, version int, fn TemplateFunc // applied to every tenant, again when the version increases
+* @return error
+**/
+func RegisterModel(name string, version int, fn TemplateFunc) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !utility.ValidStr(name, 0, []string{}) {
+		return fmt.Errorf(jdb.MSG_ATTRIBUTE_REQUIRED, "name")
+	}
+
+	template := &Template{Name: name, Version: version, fn: fn}
+	idx := slices.IndexFunc(templates, func(t *Template) bool { return t.Name == name })
+	if idx != -1 {
+		templates[idx] = template
+		return nil
+	}
+
+	templates = append(templates, template)
+	return nil
+}
+
+/**
+* getRecord
+* @param tenantId string
+* @return et.Json, error
+**/
+func getRecord(tenantId string) (et.Json, error) {
+	if registry == nil {
+		return nil, ErrRegistryRequired
+	}
+
+	item, err := jdb.NewQuery(registry, "A").
+		Where(jdb.Eq("A.id", tenantId)).
+		One()
+	if errors.Is(err, jdb.ErrNotFound) {
+		return nil, ErrTenantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return item.Result, nil
+}
+
+/**
+* setRecord
+* @param tenantId string, data et.Json
+* @return error
+**/
+func setRecord(tenantId string, data et.Json) error {
+	data["id"] = tenantId
+	data["updated_at"] = timezone.Now()
+	_, err := registry.
+		Upsert(data).
+		Exec()
+	return err
+}
+
+/**
+* connect
+* @param tenantId string, record et.Json
+* @return *jdb.DB, error
+**/
+func connect(tenantId string, record et.Json) (*jdb.DB, error) {
+	tenant, ok := tenants[tenantId]
+	if ok {
+		return tenant.DB, nil
+	}
+
+	if record.Str("mode") == ModeSchema {
+		tenant = newTenant(tenantId, shared)
+		tenant.Schema = record.Str("schema")
+		tenants[tenantId] = tenant
+		return shared, nil
+	}
+
+	result, err := jdb.Connect(record.Str("database"), record.Json("params"))
+	if err != nil {
+		return nil, err
+	}
+
+	tenants[tenantId] = newTenant(tenantId, result)
+	return result, nil
+}
+
+/**
+* load
+* @param tenantId string, record et.Json
+* @return *Tenant, []*Template, error // connects the tenant and snapshots the templates to apply
+**/
+func load(tenantId string, record et.Json) (*Tenant, []*Template, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	_, err := connect(tenantId, record)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tenants[tenantId], slices.Clone(templates), nil
+}
+
+/**
+* apply
+* @param tenant *Tenant, items []*Template, versions et.Json
+* @return et.Json, error // the versions of the templates applied
+**/
+func apply(tenant *Tenant, items []*Template, versions et.Json) (et.Json, error) {
+	result := et.Json{}
+	for k, v := range versions {
+		result[k] = v
+	}
+	for _, template := range items {
+		if versions.Int(template.Name) >= template.Version {
+			continue
+		}
+
+		model, err := template.fn(tenant.DB)
+		if err != nil {
+			return result, err
+		}
+
+		if tenant.Schema != "" {
+			model, err = model.Bind(tenant.Schema)
+		}
+		if err == nil {
+			err = model.Migrate()
+		}
+		if err != nil {
+			return result, err
+		}
+
+		mu.Lock()
+		tenant.Models[model.Name] = model
+		mu.Unlock()
+		result[template.Name] = template.Version
+	}
+
+	return result, nil
+}
+
+/**
+* Provision
+* @param tenantId string, overrides et.Json // overrides of the default connection params
+* @return *Tenant, error
+**/
+func Provision(tenantId string, overrides et.Json) (*Tenant, error) {
+	return provision(tenantId, func() et.Json {
+		connection := et.Json{}
+		for k, v := range defaults {
+			connection[k] = v
+		}
+		for k, v := range overrides {
+			connection[k] = v
+		}
+		database := connection.Str("database")
+		if database == "" {
+			database = tenantId
+		}
+		database = utility.Normalize(database)
+		connection["database"] = database
+		return et.Json{
+			"mode":       ModeDatabase,
+			"database":   database,
+			"schema":     "",
+			"status":     StatusActive,
+			"params":     connection,
+			"versions":   et.Json{},
+			"created_at": timezone.Now(),
+		}
+	})
+}
+
+/**
+* ProvisionSchema
+* @param tenantId string // the tenant gets its own schema in the core database
+* @return *Tenant, error
+**/
+func ProvisionSchema(tenantId string) (*Tenant, error) {
+	return provision(tenantId, func() et.Json {
+		return et.Json{
+			"mode":       ModeSchema,
+			"database":   shared.Name,
+			"schema":     utility.Normalize(tenantId),
+			"status":     StatusActive,
+			"params":     et.Json{},
+			"versions":   et.Json{},
+			"created_at": timezone.Now(),
+		}
+	})
+}
+
+/**
+* provision
+* @param tenantId string, newRecord func() et.Json
+* @return *Tenant, error
+**/
+func provision(tenantId string, newRecord func() et.Json) (*Tenant, error) {
+	migrations.Lock()
+	defer migrations.Unlock()
+
+	if registry == nil {
+		return nil, ErrRegistryRequired
+	}
+
+	if !utility.ValidStr(tenantId, 0, []string{}) {
+		return nil, fmt.Errorf(jdb.MSG_ATTRIBUTE_REQUIRED, "tenant_id")
+	}
+
+	record, err := getRecord(tenantId)
+	if err != nil && !errors.Is(err, ErrTenantNotFound) {
+		return nil, err
+	}
+
+	if record == nil {
+		record = newRecord()
+	}
+
+	err = checkStatus(record)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, items, err := load(tenantId, record)
+	if err != nil {
+		return nil, err
+	}
+
+	err = migrateTenant(tenant, items, record)
+	if err != nil {
+		return nil, err
+	}
+
+	return tenant, nil
+}
+
+/**
+* migrateTenant
+* @param tenant *Tenant, items []*Template, record et.Json
+* @return error
+**/
+func migrateTenant(tenant *Tenant, items []*Template, record et.Json) error {
+	versions, err := apply(tenant, items, record.Json("versions"))
+	record["versions"] = versions
+	record["migrated_at"] = timezone.Now()
+	if err != nil {
+		record["status"] = StatusFailed
+		record["error"] = err.Error()
+	} else {
+		record["status"] = StatusActive
+		record["error"] = ""
+	}
+
+	errR := setRecord(tenant.ID, record)
+	if err != nil {
+		return err
+	}
+
+	return errR
+}
+
+/**
+* Migrate
+* @return []et.Json, error // the status of every tenant, a tenant that fails does not stop the others
+**/
+func Migrate() ([]et.Json, error) {
+	migrations.Lock()
+	defer migrations.Unlock()
+
+	if registry == nil {
+		return nil, ErrRegistryRequired
+	}
+
+	items, err := jdb.NewQuery(registry, "A").
+		Where(jdb.In("A.status", []string{StatusActive, StatusFailed})).
+		All()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []et.Json{}
+	for _, record := range items.Result {
+		tenantId := record.Str("id")
+		status := et.Json{"id": tenantId, "status": StatusActive}
+		tenant, items, err := load(tenantId, record)
+		if err == nil {
+			err = migrateTenant(tenant, items, record)
+		}
+		if err != nil {
+			status["status"] = StatusFailed
+			status["error"] = err.Error()
+		}
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+/**
+* setStatus
+* @param tenantId, status string
+* @return error
+**/
+func setStatus(tenantId, status string) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	record, err := getRecord(tenantId)
+	if err != nil {
+		return err
+	}
+
+	if record.Str("status") == StatusArchived {
+		return ErrTenantArchived
+	}
+
+	err = setRecord(tenantId, et.Json{"status": status})
+	if err != nil {
+		return err
+	}
+
+	tenant, ok := tenants[tenantId]
+	if !ok || status != StatusSuspended {
+		return nil
+	}
+
+	delete(tenants, tenantId)
+	if tenant.Schema != "" {
+		return nil
+	}
+
+	return tenant.DB.Close()
+}
+
+/**
+* Suspend
+* @param tenantId string
+* @return error
+**/
+func Suspend(tenantId string) error {
+	return setStatus(tenantId, StatusSuspended)
+}
+
+/**
+* Resume
+* @param tenantId string
+* @return error
+**/
+func Resume(tenantId string) error {
+	return setStatus(tenantId, StatusActive)
+}
+
+/**
+* List
+* @param status string // optional
+* @return et.Items, error
+**/
+func List(status string) (et.Items, error) {
+	if registry == nil {
+		return et.Items{}, ErrRegistryRequired
+	}
+
+	ql := jdb.NewQuery(registry, "A").
+		Select("A.id", "A.database", "A.status", "A.versions", "A.error", "A.migrated_at", "A.created_at", "A.updated_at").
+		OrderBy("A.id")
+	if status != "" {
+		ql.Where(jdb.Eq("A.status", status))
+	}
+
+	return ql.All()
+}
+
+/**
+* Deprovision
+* @param tenantId string, archive bool // archive renames the database instead of dropping it
+* @return error
+**/
+func Deprovision(tenantId string, archive bool) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	record, err := getRecord(tenantId)
+	if err != nil {
+		return err
+	}
+
+	db, err := connect(tenantId, record)
+	if err != nil {
+		return err
+	}
+
+	if record.Str("mode") == ModeSchema {
+		err = deprovisionSchema(tenantId, record.Str("schema"), archive)
+		if err != nil {
+			return err
+		}
+
+		delete(tenants, tenantId)
+		return nil
+	}
+
+	if !archive {
+		err = db.Drop()
+		if err != nil {
+			return err
+		}
+
+		delete(tenants, tenantId)
+
+		_, err = registry.
+			Delete().
+			Where(jdb.Eq("id", tenantId)).
+			Exec()
+		return err
+	}
+
+	name := fmt.Sprintf("%s_archived_%d", db.Name, timezone.Now().Unix())
+	err = db.Archive(name)
+	if err != nil {
+		return err
+	}
+
+	delete(tenants, tenantId)
+	return setRecord(tenantId, et.Json{"status": StatusArchived, "database": name})
+}
+
+/**
+* deprovisionSchema
+* @param tenantId, schema string, archive bool
+* @return error
+**/
+func deprovisionSchema(tenantId, schema string, archive bool) error {
+	if !archive {
+		err := shared.DropSchema(schema)
+		if err != nil {
+			return err
+		}
+
+		_, err = registry.
+			Delete().
+			Where(jdb.Eq("id", tenantId)).
+			Exec()
+		return err
+	}
+
+	name := fmt.Sprintf("%s_archived_%d", schema, timezone.Now().Unix())
+	err := shared.ArchiveSchema(schema, name)
+	if err != nil {
+		return err
+	}
+
+	return setRecord(tenantId, et.Json{"status": StatusArchived, "schema": name})
+}
@@ -9,16 +9,23 @@ import (
 )
 
 const (
-	MSG_TENANT_NOT_FOUND = "tenant not found"
+	MSG_TENANT_NOT_FOUND     = "tenant not found"
+	MSG_TENANT_SUSPENDED     = "tenant suspended"
+	MSG_TENANT_ARCHIVED      = "tenant archived"
+	MSG_TENANT_REGISTRY_INIT = "tenant registry not initialized"
 )
 
 var (
-	ErrTenantNotFound = fmt.Errorf(MSG_TENANT_NOT_FOUND)
+	ErrTenantNotFound   = fmt.Errorf(MSG_TENANT_NOT_FOUND)
+	ErrTenantSuspended  = fmt.Errorf(MSG_TENANT_SUSPENDED)
+	ErrTenantArchived   = fmt.Errorf(MSG_TENANT_ARCHIVED)
+	ErrRegistryRequired = fmt.Errorf(MSG_TENANT_REGISTRY_INIT)
 )
 
 type Tenant struct {
 	ID     string                `json:"id"`
 	DB     *jdb.DB               `json:"db"`
+	Schema string                `json:"schema"`
 	Models map[string]*jdb.Model `json:"models"`
 }
 
@@ -60,17 +67,50 @@ func init() {
 	tenants = make(map[string]*Tenant)
 }
 
+/**
+* checkStatus
+* @param record et.Json
+* @return error // suspended and archived tenants can not be connected
+**/
+func checkStatus(record et.Json) error {
+	switch record.Str("status") {
+	case StatusSuspended:
+		return ErrTenantSuspended
+	case StatusArchived:
+		return ErrTenantArchived
+	}
+
+	return nil
+}
+
 /**
 * GetDb
 * @param id string
 * @return (*DB, error)
 **/
 func GetDb(id string) (*jdb.DB, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
 	tenant, ok := tenants[id]
 	if ok {
 		return tenant.DB, nil
 	}
 
+	if registry != nil {
+		record, err := getRecord(id)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkStatus(record)
+		if err != nil {
+			return nil, err
+		}
+
+		return connect(id, record)
+	}
+
 	result, err := jdb.GetDb(id)
 	if err != nil {
 		return nil, jdb.ErrDbNotFound
@@ -86,7 +126,28 @@ func GetDb(id string) (*jdb.DB, error) {
 * @return (*Model, error)
 **/
 func GetModel(tenantId, name string) (*jdb.Model, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
 	tenant, ok := tenants[tenantId]
+	if !ok && registry != nil {
+		record, err := getRecord(tenantId)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkStatus(record)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = connect(tenantId, record)
+		if err != nil {
+			return nil, err
+		}
+		tenant, ok = tenants[tenantId]
+	}
+
 	if !ok {
 		return nil, ErrTenantNotFound
 	}
@@ -101,6 +162,13 @@ func GetModel(tenantId, name string) (*jdb.Model, error) {
 		return nil, err
 	}
 
+	if tenant.Schema != "" {
+		result, err = result.Bind(tenant.Schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	tenant.Models[name] = result
 	return result, nil
 }
@@ -111,6 +179,9 @@ func GetModel(tenantId, name string) (*jdb.Model, error) {
 * @return *jdb.DB, error
 **/
 func NewDb(tenantId string, params et.Json) (*jdb.DB, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
 	tenant, ok := tenants[tenantId]
 	if ok {
 		return tenant.DB, nil
@@ -1,12 +1,49 @@
 package jql
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/cgalvisleon/et/envar"
 	"github.com/cgalvisleon/et/request"
 	"github.com/cgalvisleon/et/response"
+	"github.com/cgalvisleon/jql/jdb"
 )
 
+/**
+* HttpStatus
+* @param err error
+* @return int
+**/
+func HttpStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound),
+		errors.Is(err, jdb.ErrModelNotFound),
+		errors.Is(err, jdb.ErrDbNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrTenantRequired):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrCrossTenant):
+		return http.StatusForbidden
+	case errors.Is(err, ErrDuplicate),
+		errors.Is(err, ErrForeignKey),
+		errors.Is(err, ErrNotUpdated),
+		errors.Is(err, ErrNotUpserted):
+		return http.StatusConflict
+	case errors.Is(err, ErrValidation),
+		errors.Is(err, ErrNotNull),
+		errors.Is(err, ErrCheck),
+		errors.Is(err, ErrNotInserted):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, ErrSerialization),
+		errors.Is(err, ErrDeadlock),
+		errors.Is(err, ErrNotConnected):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 /**
 * HttpDefine
 * @param w http.ResponseWriter, r *http.Request
@@ -21,7 +58,7 @@ func HttpDefine(w http.ResponseWriter, r *http.Request) {
 
 	result, err := Define(body)
 	if err != nil {
-		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		response.HTTPError(w, r, HttpStatus(err), err.Error())
 		return
 	}
 
@@ -40,11 +77,37 @@ func HttpQuery(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := Query(body)
+	result, err := QueryContext(r.Context(), body)
 	if err != nil {
-		response.HTTPError(w, r, http.StatusBadRequest, err.Error())
+		response.HTTPError(w, r, HttpStatus(err), err.Error())
 		return
 	}
 
 	response.ITEMS(w, r, http.StatusOK, result)
 }
+
+/**
+* HttpHealth
+* @param w http.ResponseWriter, r *http.Request // ?database=name, DB_NAME by default
+* @return
+**/
+func HttpHealth(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("database")
+	if name == "" {
+		name = envar.GetStr("DB_NAME", "josephine")
+	}
+
+	db, err := jdb.GetDb(name)
+	if err != nil {
+		response.HTTPError(w, r, HttpStatus(err), err.Error())
+		return
+	}
+
+	result, err := db.Health(r.Context())
+	if err != nil {
+		response.JSON(w, r, http.StatusServiceUnavailable, result)
+		return
+	}
+
+	response.JSON(w, r, http.StatusOK, result)
+}
@@ -1,7 +1,11 @@
 package jql
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/cgalvisleon/et/envar"
 	"github.com/cgalvisleon/et/et"
@@ -34,13 +38,28 @@ const (
 	DATETIME = jdb.DATETIME
 	BOOLEAN  = jdb.BOOLEAN
 	GEOMETRY = jdb.GEOMETRY
+	TSVECTOR = jdb.TSVECTOR
+	DECIMAL  = jdb.DECIMAL
+	UUID     = jdb.UUID
+	DATE     = jdb.DATE
+	TIME     = jdb.TIME
+	INTERVAL = jdb.INTERVAL
+	ARRAY    = jdb.ARRAY
+	ENUM     = jdb.ENUM
 	CALC     = jdb.CALC
 	// Column types
-	COLUMN = jdb.COLUMN
-	ATTRIB = jdb.ATTRIB
-	DETAIL = jdb.DETAIL
-	ROLLUP = jdb.ROLLUP
-	AGG    = jdb.AGG
+	COLUMN   = jdb.COLUMN
+	ATTRIB   = jdb.ATTRIB
+	DETAIL   = jdb.DETAIL
+	ROLLUP   = jdb.ROLLUP
+	AGG      = jdb.AGG
+	SUBQUERY = jdb.SUBQUERY
+	// Index methods
+	BTREE = jdb.BTREE
+	GIN   = jdb.GIN
+	GIST  = jdb.GIST
+	BRIN  = jdb.BRIN
+	HASH  = jdb.HASH
 	// Status record
 	ACTIVE     = jdb.ACTIVE
 	ARCHIVED   = jdb.ARCHIVED
@@ -54,11 +73,23 @@ const (
 
 var (
 	// Error
-	ErrNotUpdated        = jdb.ErrNotUpdated
-	ErrNotInserted error = fmt.Errorf("record not inserted")
-	ErrNotFound    error = fmt.Errorf("record not found")
-	ErrNotUpserted error = fmt.Errorf("record not inserted or updated")
-	ErrDuplicate   error = fmt.Errorf("record duplicate")
+	ErrNotUpdated     = jdb.ErrNotUpdated
+	ErrNotInserted    = jdb.ErrNotInserted
+	ErrNotFound       = jdb.ErrNotFound
+	ErrNotUpserted    = jdb.ErrNotUpserted
+	ErrDuplicate      = jdb.ErrDuplicate
+	ErrForeignKey     = jdb.ErrForeignKey
+	ErrNotNull        = jdb.ErrNotNull
+	ErrCheck          = jdb.ErrCheck
+	ErrSerialization  = jdb.ErrSerialization
+	ErrDeadlock       = jdb.ErrDeadlock
+	ErrValidation     = jdb.ErrValidation
+	ErrInvalidCursor  = jdb.ErrInvalidCursor
+	ErrTenantRequired = jdb.ErrTenantRequired
+	ErrCrossTenant    = jdb.ErrCrossTenant
+	ErrNotConnected   = jdb.ErrNotConnected
+	// Status values
+	StatusValues = jdb.StatusValues
 )
 
 type TypeColumn = jdb.TypeColumn
@@ -70,6 +101,23 @@ type Tx = jdb.Tx
 type Condition = jdb.Condition
 type Ql = jdb.Ql
 type Cmd = jdb.Cmd
+type DbError = jdb.DbError
+type ValidationError = jdb.ValidationError
+type Page = jdb.Page
+type Cte = jdb.Cte
+type Agg = jdb.Agg
+type Window = jdb.Window
+type IndexSpec = jdb.IndexSpec
+type Check = jdb.Check
+type Cache = jdb.Cache
+type MemoryCache = jdb.MemoryCache
+type QueryEvent = jdb.QueryEvent
+type QueryHook = jdb.QueryHook
+type Metrics = jdb.Metrics
+type Span = jdb.Span
+type SpanExporter = jdb.SpanExporter
+type SpanContext = jdb.SpanContext
+type AsyncExporter = jdb.AsyncExporter
 
 /**
 * ConnectTo
@@ -80,6 +128,32 @@ func ConnectTo(name string, params et.Json) (*jdb.DB, error) {
 	return jdb.Connect(name, params)
 }
 
+/**
+* replicas
+* @param hosts string // host:port separated by comma
+* @return []et.Json
+**/
+func replicas(hosts string) []et.Json {
+	result := make([]et.Json, 0)
+	for _, host := range strings.Split(hosts, ",") {
+		host = strings.TrimSpace(host)
+		if host == "" {
+			continue
+		}
+
+		item := et.Json{"host": host}
+		if idx := strings.LastIndex(host, ":"); idx > 0 {
+			port, err := strconv.Atoi(host[idx+1:])
+			if err == nil {
+				item = et.Json{"host": host[:idx], "port": port}
+			}
+		}
+		result = append(result, item)
+	}
+
+	return result
+}
+
 /**
 * LoadTo
 * @param name string
@@ -87,15 +161,30 @@ func ConnectTo(name string, params et.Json) (*jdb.DB, error) {
 **/
 func LoadTo(name string, host string, port int) (*jdb.DB, error) {
 	params := et.Json{
-		"driver":   envar.GetStr("DB_DRIVER", "postgres"),
-		"database": name,
-		"host":     host,
-		"port":     port,
-		"username": envar.GetStr("DB_USERNAME", "test"),
-		"password": envar.GetStr("DB_PASSWORD", "test"),
-		"app":      envar.GetStr("DB_APP", "jql"),
-		"version":  envar.GetInt("DB_VERSION", 15),
-		"use_core": envar.GetBool("DB_USE_CORE", false),
+		"driver":             envar.GetStr("DB_DRIVER", "postgres"),
+		"database":           name,
+		"host":               host,
+		"port":               port,
+		"username":           envar.GetStr("DB_USERNAME", "test"),
+		"password":           envar.GetStr("DB_PASSWORD", "test"),
+		"app":                envar.GetStr("DB_APP", "jql"),
+		"version":            envar.GetInt("DB_VERSION", 15),
+		"use_core":           envar.GetBool("DB_USE_CORE", false),
+		"sslmode":            envar.GetStr("DB_SSLMODE", "disable"),
+		"sslrootcert":        envar.GetStr("DB_SSLROOTCERT", ""),
+		"sslcert":            envar.GetStr("DB_SSLCERT", ""),
+		"sslkey":             envar.GetStr("DB_SSLKEY", ""),
+		"connect_timeout":    envar.GetInt("DB_CONNECT_TIMEOUT", 10),
+		"statement_timeout":  envar.GetInt("DB_STATEMENT_TIMEOUT", 0),
+		"search_path":        envar.GetStr("DB_SEARCH_PATH", ""),
+		"max_open_conns":     envar.GetInt("DB_MAX_OPEN_CONNS", 0),
+		"max_idle_conns":     envar.GetInt("DB_MAX_IDLE_CONNS", 0),
+		"conn_max_lifetime":  envar.GetInt("DB_CONN_MAX_LIFETIME", 0),
+		"conn_max_idle_time": envar.GetInt("DB_CONN_MAX_IDLE_TIME", 0),
+		"replicas":           replicas(envar.GetStr("DB_REPLICAS", "")),
+		"read_your_writes":   envar.GetInt("DB_READ_YOUR_WRITES", 0),
+		"replica_eject":      envar.GetInt("DB_REPLICA_EJECT", 30),
+		"slow_query":         envar.GetInt("DB_SLOW_QUERY", 0),
 	}
 
 	return ConnectTo(name, params)
@@ -137,7 +226,27 @@ func Define(params et.Json) (*jdb.Model, error) {
 * @return (et.Items, error)
 **/
 func Query(params et.Json) (et.Items, error) {
-	return et.Items{}, nil
+	return QueryContext(context.Background(), params)
+}
+
+/**
+* QueryContext
+* @param ctx context.Context, params et.Json // the tenant comes only from the context, the tenant_id of params is ignored
+* @return (et.Items, error)
+**/
+func QueryContext(ctx context.Context, params et.Json) (et.Items, error) {
+	database := params.String("database")
+	if !utility.ValidStr(database, 0, []string{}) {
+		return et.Items{}, fmt.Errorf(jdb.MSG_ATTRIBUTE_REQUIRED, "database")
+	}
+
+	db, err := jdb.GetDb(database)
+	if err != nil {
+		return et.Items{}, err
+	}
+
+	delete(params, "tenant_id")
+	return db.QueryContext(ctx, params)
 }
 
 /**
@@ -148,3 +257,117 @@ func Query(params et.Json) (et.Items, error) {
 func From(model *jdb.Model, as string) *jdb.Ql {
 	return jdb.NewQuery(model, as)
 }
+
+/**
+* FromQuery
+* @param ql *jdb.Ql, as string
+* @return *jdb.Ql
+**/
+func FromQuery(ql *jdb.Ql, as string) *jdb.Ql {
+	return jdb.NewQueryFrom(ql, as)
+}
+
+/**
+* With
+* @param name string, ql *jdb.Ql
+* @return *jdb.Ql
+**/
+func With(name string, ql *jdb.Ql) *jdb.Ql {
+	return jdb.With(name, ql)
+}
+
+/**
+* WithRecursive
+* @param name string, anchor, recursive *jdb.Ql
+* @return *jdb.Ql
+**/
+func WithRecursive(name string, anchor, recursive *jdb.Ql) *jdb.Ql {
+	return jdb.WithRecursive(name, anchor, recursive)
+}
+
+/**
+* CteModel
+* @param name string, ql *jdb.Ql
+* @return *jdb.Model
+**/
+func CteModel(name string, ql *jdb.Ql) *jdb.Model {
+	return jdb.CteModel(name, ql)
+}
+
+/**
+* WithTenant
+* @param ctx context.Context, tenantId string
+* @return context.Context
+**/
+func WithTenant(ctx context.Context, tenantId string) context.Context {
+	return jdb.WithTenant(ctx, tenantId)
+}
+
+/**
+* SetCache
+* @param cache Cache
+**/
+func SetCache(cache Cache) {
+	jdb.SetCache(cache)
+}
+
+/**
+* OnInvalidate
+* @param fn func(model string)
+**/
+func OnInvalidate(fn func(model string)) {
+	jdb.OnInvalidate(fn)
+}
+
+/**
+* InvalidateModel
+* @param model string
+**/
+func InvalidateModel(model string) {
+	jdb.InvalidateModel(model)
+}
+
+/**
+* NewMetrics
+* @param buckets ...float64
+* @return *Metrics
+**/
+func NewMetrics(buckets ...float64) *Metrics {
+	return jdb.NewMetrics(buckets...)
+}
+
+/**
+* SlowQueryLog
+* @param threshold time.Duration
+* @return QueryHook
+**/
+func SlowQueryLog(threshold time.Duration) QueryHook {
+	return jdb.SlowQueryLog(threshold)
+}
+
+/**
+* Tracer
+* @param exporter SpanExporter, system string
+* @return QueryHook
+**/
+func Tracer(exporter SpanExporter, system string) QueryHook {
+	return jdb.Tracer(exporter, system)
+}
+
+/**
+* NewAsyncExporter
+* @param exporter SpanExporter, size int
+* @return *AsyncExporter
+**/
+func NewAsyncExporter(exporter SpanExporter, size int) *AsyncExporter {
+	return jdb.NewAsyncExporter(exporter, size)
+}
+
+/**
+* WithSpan
+* @param ctx context.Context, traceId, spanId string
+* @return context.Context
+**/
+func WithSpan(ctx context.Context, traceId, spanId string) context.Context {
+	return jdb.WithSpan(ctx, traceId, spanId)
+}
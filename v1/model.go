@@ -55,3 +55,30 @@ func DeleteModel(database, schema, name string) error {
 
 	return nil
 }
+
+/**
+* DefineFromStruct
+* @param db *DB, schema, name string, version int
+* @return (*Model, error)
+**/
+func DefineFromStruct[T any](db *DB, schema, name string, version int) (*Model, error) {
+	return jdb.DefineFromStruct[T](db, schema, name, version)
+}
+
+/**
+* QueryTo
+* @param ql *Ql
+* @return ([]T, error)
+**/
+func QueryTo[T any](ql *Ql) ([]T, error) {
+	return jdb.Query[T](ql)
+}
+
+/**
+* OneTo
+* @param ql *Ql
+* @return (T, bool, error)
+**/
+func OneTo[T any](ql *Ql) (T, bool, error) {
+	return jdb.One[T](ql)
+}
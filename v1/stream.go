@@ -0,0 +1,132 @@
+package jql
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/cgalvisleon/et/et"
+	"github.com/cgalvisleon/et/logs"
+	"github.com/cgalvisleon/et/response"
+)
+
+const FLUSH_ROWS int = 100
+
+/**
+* csvValue
+* @param val interface{}
+* @return string
+**/
+func csvValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, et.Json, []interface{}, []et.Json:
+		bt, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(bt)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+/**
+* csvHeader
+* @param ql *Ql, item et.Json
+* @return []string
+**/
+func csvHeader(ql *Ql, item et.Json) []string {
+	result := []string{}
+	for _, fld := range ql.Selects {
+		result = append(result, fld.As)
+	}
+
+	if len(result) > 0 {
+		return result
+	}
+
+	for k := range item {
+		result = append(result, k)
+	}
+	slices.Sort(result)
+	return result
+}
+
+/**
+* HttpStream
+* @param w http.ResponseWriter, r *http.Request, ql *Ql // ?format=csv or Accept: text/csv, ndjson by default
+* @return
+**/
+func HttpStream(w http.ResponseWriter, r *http.Request, ql *Ql) {
+	isCsv := r.URL.Query().Get("format") == "csv" || strings.Contains(r.Header.Get("Accept"), "text/csv")
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	writer := csv.NewWriter(w)
+	header := []string{}
+	count := 0
+
+	err := ql.WithContext(r.Context()).Each(func(item et.Json) error {
+		if count == 0 && isCsv {
+			w.Header().Set("Content-Type", "text/csv")
+			w.WriteHeader(http.StatusOK)
+			header = csvHeader(ql, item)
+			if err := writer.Write(header); err != nil {
+				return err
+			}
+		} else if count == 0 {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.WriteHeader(http.StatusOK)
+		}
+
+		if isCsv {
+			record := make([]string, len(header))
+			for i, k := range header {
+				record[i] = csvValue(item[k])
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		} else if err := encoder.Encode(item); err != nil {
+			return err
+		}
+
+		count++
+		if count%FLUSH_ROWS == 0 {
+			writer.Flush()
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		return nil
+	})
+	if err != nil && count == 0 {
+		response.HTTPError(w, r, HttpStatus(err), err.Error())
+		return
+	}
+
+	if err != nil {
+		logs.Errorf("HttpStream, error after %d rows: %v", count, err)
+	}
+
+	if count == 0 {
+		if isCsv {
+			w.Header().Set("Content-Type", "text/csv")
+		} else {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+
+	writer.Flush()
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
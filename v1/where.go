@@ -67,19 +67,19 @@ func Like(field string, value interface{}) *jdb.Condition {
 
 /**
 * In
-* @param field string, value []interface{}
+* @param field string, value interface{} // []interface{} or *Ql
 * @return jdb.Condition
 **/
-func In(field string, value []interface{}) *jdb.Condition {
+func In(field string, value interface{}) *jdb.Condition {
 	return jdb.In(field, value)
 }
 
 /**
 * NotIn
-* @param field string, value []interface{}
+* @param field string, value interface{} // []interface{} or *Ql
 * @return jdb.Condition
 **/
-func NotIn(field string, value []interface{}) *jdb.Condition {
+func NotIn(field string, value interface{}) *jdb.Condition {
 	return jdb.NotIn(field, value)
 }
 
@@ -136,3 +136,48 @@ func Between(field string, min, max interface{}) *jdb.Condition {
 func NotBetween(field string, min, max interface{}) *jdb.Condition {
 	return jdb.NotBetween(field, min, max)
 }
+
+/**
+* ILike
+* @param field string, value interface{}
+* @return jdb.Condition
+**/
+func ILike(field string, value interface{}) *jdb.Condition {
+	return jdb.ILike(field, value)
+}
+
+/**
+* Similar
+* @param field string, value interface{}
+* @return jdb.Condition
+**/
+func Similar(field string, value interface{}) *jdb.Condition {
+	return jdb.Similar(field, value)
+}
+
+/**
+* Exists
+* @param ql *Ql
+* @return jdb.Condition
+**/
+func Exists(ql *Ql) *jdb.Condition {
+	return jdb.Exists(ql)
+}
+
+/**
+* NotExists
+* @param ql *Ql
+* @return jdb.Condition
+**/
+func NotExists(ql *Ql) *jdb.Condition {
+	return jdb.NotExists(ql)
+}
+
+/**
+* Ref
+* @param name string
+* @return *jdb.Field
+**/
+func Ref(name string) *jdb.Field {
+	return jdb.Ref(name)
+}